@@ -0,0 +1,37 @@
+package spans
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// maxBytesPerBaggageMember is the limit of the size of a member defined by the W3C Baggage specification.
+const maxBytesPerBaggageMember = 4096
+
+// ContextWithBaggage returns a copy of ctx whose baggage has attrs as members in addition to the existing ones,
+// so that they propagate to downstream services. Structs are flattened with the same rules as ObjectAttr,
+// and all the values are stringified because baggage has no types.
+// It returns an error with the original context if the result exceeds the limits of the W3C Baggage specification,
+// which are 4096 bytes per member, 180 members and 8192 bytes in total.
+func ContextWithBaggage(ctx context.Context, attrs ...KeyValue) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+	members := bag.Members()
+	for _, attr := range getStandardAttributes(attrs) {
+		member, err := baggage.NewMemberRaw(string(attr.Key), attr.Value.Emit())
+		if err != nil {
+			return ctx, fmt.Errorf("error NewMemberRaw: key=>%s: %w", attr.Key, err)
+		}
+		if n := len(member.String()); n > maxBytesPerBaggageMember {
+			return ctx, fmt.Errorf("baggage member %s exceeds the limit: %d bytes", attr.Key, n)
+		}
+		members = append(members, member)
+	}
+
+	newBag, err := baggage.New(members...)
+	if err != nil {
+		return ctx, fmt.Errorf("error baggage.New: %w", err)
+	}
+	return baggage.ContextWithBaggage(ctx, newBag), nil
+}
@@ -0,0 +1,43 @@
+package spans
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestContextWithBaggage(t *testing.T) {
+	ctx, err := ContextWithBaggage(context.Background(), StringAttr("existing", "yes"))
+	require.NoError(t, err)
+	ctx, err = ContextWithBaggage(ctx,
+		ObjectAttr("tenant", testObject{ID: 1, Name: "foo bar"}),
+		StringSliceAttr("arms", []string{"a", "b"}),
+	)
+	require.NoError(t, err)
+
+	bag := baggage.FromContext(ctx)
+	assert.Equal(t, 5, bag.Len())
+	assert.Equal(t, "yes", bag.Member("existing").Value())
+	assert.Equal(t, "1", bag.Member("tenant.id").Value())
+	assert.Equal(t, "foo bar", bag.Member("tenant.name").Value())
+	assert.Equal(t, "false", bag.Member("tenant.admin").Value())
+	assert.Equal(t, `["a","b"]`, bag.Member("arms").Value())
+}
+
+func TestContextWithBaggage__ExceedingLimits(t *testing.T) {
+	ctx := context.Background()
+	_, err := ContextWithBaggage(ctx, StringAttr("large", strings.Repeat("a", 4096)))
+	assert.Error(t, err)
+
+	attrs := make([]KeyValue, 0, 3)
+	for _, key := range []string{"a", "b", "c"} {
+		attrs = append(attrs, StringAttr(key, strings.Repeat("a", 3000)))
+	}
+	got, err := ContextWithBaggage(ctx, attrs...)
+	assert.Error(t, err)
+	assert.Equal(t, ctx, got)
+}
@@ -0,0 +1,131 @@
+package spans
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type collectorKey struct{}
+
+// collector holds the attributes collected during a request in the order of their first appearance.
+type collector struct {
+	mu     sync.Mutex
+	keys   []attribute.Key
+	values map[attribute.Key]attribute.Value
+}
+
+func (c *collector) collect(attributes []attribute.KeyValue, accumulate bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, attr := range attributes {
+		prev, ok := c.values[attr.Key]
+		if !ok {
+			c.keys = append(c.keys, attr.Key)
+			c.values[attr.Key] = attr.Value
+			continue
+		}
+		if accumulate {
+			c.values[attr.Key] = accumulateValue(prev, attr.Value)
+		} else {
+			c.values[attr.Key] = attr.Value
+		}
+	}
+}
+
+func (c *collector) attributes() []attribute.KeyValue {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	attributes := make([]attribute.KeyValue, 0, len(c.keys))
+	for _, key := range c.keys {
+		attributes = append(attributes, attribute.KeyValue{Key: key, Value: c.values[key]})
+	}
+	return attributes
+}
+
+// ContextWithCollector returns a copy of ctx with a new collector for Collect and Accumulate,
+// whose attributes are set on a span with FlushCollected.
+// Start does it automatically for local root spans, so this is needed only for spans started in other ways.
+func ContextWithCollector(ctx context.Context) context.Context {
+	return context.WithValue(ctx, collectorKey{}, &collector{
+		values: make(map[attribute.Key]attribute.Value),
+	})
+}
+
+// Collect records attrs to be set on the local root span (e.g. the span of the request) instead of the current one,
+// which is useful for facts learned deep in the code such as cache hits or feature flags.
+// If the same key is collected more than once, the last value wins. It is safe for concurrent use.
+// It does nothing if ctx has no collector, e.g. the root span is not recording.
+func Collect(ctx context.Context, attrs ...KeyValue) {
+	if c, ok := ctx.Value(collectorKey{}).(*collector); ok {
+		c.collect(getStandardAttributes(attrs), false)
+	}
+}
+
+// Accumulate is the same as Collect, but it accumulates the values of the same key into a slice.
+// For example, StringAttr("flags", "a") and StringAttr("flags", "b") result in flags=["a","b"].
+// If the types of the values do not match, the last value wins like Collect.
+func Accumulate(ctx context.Context, attrs ...KeyValue) {
+	if c, ok := ctx.Value(collectorKey{}).(*collector); ok {
+		c.collect(getStandardAttributes(attrs), true)
+	}
+}
+
+// FlushCollected sets the attributes collected in ctx on the span.
+func FlushCollected(ctx context.Context, span trace.Span) {
+	if c, ok := ctx.Value(collectorKey{}).(*collector); ok {
+		span.SetAttributes(c.attributes()...)
+	}
+}
+
+// accumulateValue concatenates the values of the same element type into a slice, or returns next otherwise.
+func accumulateValue(prev, next attribute.Value) attribute.Value {
+	if elementType(prev.Type()) != elementType(next.Type()) {
+		return next
+	}
+
+	//nolint:exhaustive
+	switch elementType(prev.Type()) {
+	case attribute.BOOL:
+		return attribute.BoolSliceValue(concatValues(prev, next, attribute.Value.AsBool, attribute.Value.AsBoolSlice))
+	case attribute.INT64:
+		return attribute.Int64SliceValue(concatValues(prev, next, attribute.Value.AsInt64, attribute.Value.AsInt64Slice))
+	case attribute.FLOAT64:
+		return attribute.Float64SliceValue(concatValues(prev, next, attribute.Value.AsFloat64, attribute.Value.AsFloat64Slice))
+	case attribute.STRING:
+		return attribute.StringSliceValue(concatValues(prev, next, attribute.Value.AsString, attribute.Value.AsStringSlice))
+	default:
+		return next
+	}
+}
+
+// elementType returns the type of the elements for slices, or the type itself for scalars.
+func elementType(t attribute.Type) attribute.Type {
+	//nolint:exhaustive
+	switch t {
+	case attribute.BOOLSLICE:
+		return attribute.BOOL
+	case attribute.INT64SLICE:
+		return attribute.INT64
+	case attribute.FLOAT64SLICE:
+		return attribute.FLOAT64
+	case attribute.STRINGSLICE:
+		return attribute.STRING
+	default:
+		return t
+	}
+}
+
+func concatValues[T any](prev, next attribute.Value, asScalar func(attribute.Value) T, asSlice func(attribute.Value) []T) []T {
+	var result []T
+	for _, v := range []attribute.Value{prev, next} {
+		if elementType(v.Type()) == v.Type() {
+			result = append(result, asScalar(v))
+		} else {
+			result = append(result, asSlice(v)...)
+		}
+	}
+	return result
+}
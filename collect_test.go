@@ -0,0 +1,64 @@
+package spans
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCollect(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+
+	func() {
+		var err error
+		ctx, end := Start(context.Background(), tracer, "root")
+		defer end(&err)
+
+		func() {
+			ctx, end := Start(ctx, tracer, "child")
+			defer end(&err)
+
+			Collect(ctx, BoolAttr("cache.hit", false), IntAttr("db.rows", 1))
+			Collect(ctx, BoolAttr("cache.hit", true))
+			Accumulate(ctx, StringAttr("flags", "a"))
+			Accumulate(ctx, StringSliceAttr("flags", []string{"b", "c"}))
+			Accumulate(ctx, StringAttr("mismatch", "a"), IntAttr("mismatch", 2))
+		}()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				Accumulate(ctx, IntAttr("concurrent", 1))
+			}()
+		}
+		wg.Wait()
+	}()
+
+	ended := recorder.Ended()
+	if !assert.Len(t, ended, 2) {
+		return
+	}
+	assert.Empty(t, ended[0].Attributes())
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.Bool("cache.hit", true),
+		attribute.Int("db.rows", 1),
+		attribute.StringSlice("flags", []string{"a", "b", "c"}),
+		attribute.Int("mismatch", 2),
+		attribute.Int64Slice("concurrent", []int64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}),
+	}, ended[1].Attributes())
+}
+
+func TestCollect__WithoutCollector(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	ctx, span := tracer.Start(context.Background(), "test")
+	Collect(ctx, BoolAttr("cache.hit", true))
+	FlushCollected(ctx, span)
+	span.End()
+
+	assert.Empty(t, recorder.Ended()[0].Attributes())
+}
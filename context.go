@@ -0,0 +1,26 @@
+package spans
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type contextAttrsKey struct{}
+
+// ContextWithAttrs returns a copy of ctx carrying attrs in addition to the ones carried by ctx itself.
+// They are set on every span started with the returned context (or its descendants)
+// if processor.ContextAttrs is registered with the TracerProvider.
+func ContextWithAttrs(ctx context.Context, attrs ...KeyValue) context.Context {
+	parent := AttrsFromContext(ctx)
+	attributes := make([]attribute.KeyValue, 0, len(parent)+len(attrs))
+	attributes = append(attributes, parent...)
+	attributes = append(attributes, getStandardAttributes(attrs)...)
+	return context.WithValue(ctx, contextAttrsKey{}, attributes)
+}
+
+// AttrsFromContext returns the attributes carried by ctx with ContextWithAttrs.
+func AttrsFromContext(ctx context.Context) []attribute.KeyValue {
+	attributes, _ := ctx.Value(contextAttrsKey{}).([]attribute.KeyValue)
+	return attributes
+}
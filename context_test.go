@@ -0,0 +1,24 @@
+package spans
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestContextWithAttrs(t *testing.T) {
+	assertAttributes(t, nil, AttrsFromContext(context.Background()))
+
+	parent := ContextWithAttrs(context.Background(), StringAttr("tenant", "t1"))
+	child := ContextWithAttrs(parent, ObjectAttr("user", testObject{ID: 1}))
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("tenant", "t1"),
+	}, AttrsFromContext(parent))
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("tenant", "t1"),
+		attribute.Int64("user.id", 1),
+		attribute.String("user.name", ""),
+		attribute.Bool("user.admin", false),
+	}, AttrsFromContext(child))
+}
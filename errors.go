@@ -0,0 +1,79 @@
+package spans
+
+import (
+	"fmt"
+	"log/slog"
+
+	pkgotel "github.com/ebi-yade/spans/pkg/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errorKey is the key under which errors in the chain contribute their attributes, e.g. error.code.
+const errorKey = "error"
+
+// RecordError can be used in place of span.RecordError to record an error with multiple attributes,
+// and it also sets the status of the span to codes.Error.
+// Errors in the chain of errors.Unwrap and errors.Join that implement otel.Marshaler, or whose types are
+// registered with otel.RegisterEncoder, contribute their attributes to the event under the key "error" (e.g. error.code).
+// If several errors in the chain produce the same key, the outermost one wins.
+func RecordError(span trace.Span, err error, attrs ...KeyValue) {
+	recordError(span, err, false, attrs)
+}
+
+// RecordErrorWithStackTrace is the same as RecordError, but it also records the stack trace on the event.
+func RecordErrorWithStackTrace(span trace.Span, err error, attrs ...KeyValue) {
+	recordError(span, err, true, attrs)
+}
+
+func recordError(span trace.Span, err error, stackTrace bool, attrs []KeyValue) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	attributes := getErrorAttributes(err)
+	attributes = append(attributes, getStandardAttributes(attrs)...)
+	span.RecordError(err, trace.WithAttributes(attributes...), trace.WithStackTrace(stackTrace))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func getErrorAttributes(err error) []attribute.KeyValue {
+	encoder := pkgotel.DefaultEncoder()
+	var attributes []attribute.KeyValue
+	seen := make(map[attribute.Key]struct{})
+	walkErrors(err, func(err error) {
+		if !encoder.CanMarshal(err) {
+			return
+		}
+		results, mErr := encoder.MarshalWithKey(errorKey, err)
+		if mErr != nil { // 解釈に失敗した場合はログを吐いて無視するようにしている
+			slog.Error(fmt.Sprintf("error MarshalWithKey: type=>%T, error=>%v", err, mErr))
+			return
+		}
+		for _, res := range results {
+			if _, ok := seen[res.Key]; ok {
+				continue
+			}
+			seen[res.Key] = struct{}{}
+			attributes = append(attributes, res)
+		}
+	})
+
+	return attributes
+}
+
+// walkErrors calls fn for err and the errors wrapped by it in depth-first order.
+func walkErrors(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrors(x.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			walkErrors(e, fn)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package spans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type testDomainError struct {
+	Code    string
+	OrderID int
+}
+
+func (e *testDomainError) Error() string {
+	return fmt.Sprintf("domain error: %s", e.Code)
+}
+
+func (e *testDomainError) MarshalOtelAttributes() ([]attribute.KeyValue, error) {
+	return []attribute.KeyValue{
+		attribute.String("code", e.Code),
+		attribute.Int("order_id", e.OrderID),
+	}, nil
+}
+
+func TestRecordError(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	_, span := tracer.Start(context.Background(), "test")
+
+	inner := &testDomainError{Code: "inner", OrderID: 2}
+	outer := &testDomainError{Code: "outer", OrderID: 1}
+	err := fmt.Errorf("wrapped: %w", errors.Join(outer, errors.New("plain"), inner))
+	RecordError(span, err, StringAttr("tenant", "foo"))
+	span.End()
+
+	got := recorder.Ended()[0]
+	assert.Equal(t, codes.Error, got.Status().Code)
+	assert.Equal(t, err.Error(), got.Status().Description)
+	if assert.Len(t, got.Events(), 1) {
+		event := got.Events()[0]
+		assert.Equal(t, "exception", event.Name)
+		eventAttrs := attribute.NewSet(event.Attributes...)
+		for _, want := range []attribute.KeyValue{
+			attribute.String("error.code", "outer"),
+			attribute.Int("error.order_id", 1),
+			attribute.String("tenant", "foo"),
+			attribute.String("exception.message", err.Error()),
+		} {
+			v, ok := eventAttrs.Value(want.Key)
+			assert.True(t, ok, "missing %s", want.Key)
+			assert.Equal(t, want.Value, v, "unexpected %s", want.Key)
+		}
+		_, ok := eventAttrs.Value("exception.stacktrace")
+		assert.False(t, ok)
+	}
+}
+
+func TestRecordErrorWithStackTrace(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	_, span := tracer.Start(context.Background(), "test")
+	RecordErrorWithStackTrace(span, errors.New("plain"))
+	RecordError(span, nil)
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if assert.Len(t, events, 1) {
+		eventAttrs := attribute.NewSet(events[0].Attributes...)
+		_, ok := eventAttrs.Value("exception.stacktrace")
+		assert.True(t, ok)
+	}
+}
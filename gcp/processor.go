@@ -1,4 +1,4 @@
-// package gcp is an abbreviation for Google Cloud 'Processor', not 'Platform'.
+// Package gcp provides integrations with Google Cloud: a SpanProcessor for Cloud Trace and a slog handler for Cloud Logging.
 package gcp
 
 import (
@@ -0,0 +1,131 @@
+package gcp
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Special fields of structured logs recognized by Cloud Logging to correlate log entries with traces
+const (
+	traceKey        = "logging.googleapis.com/trace"
+	spanIDKey       = "logging.googleapis.com/spanId"
+	traceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// projectIDEnvs are the environment variables to detect the project ID, in order of precedence
+var projectIDEnvs = []string{"GOOGLE_CLOUD_PROJECT", "GCP_PROJECT", "GCLOUD_PROJECT"}
+
+// SlogHandlerOptions are options for the handler created by NewSlogHandler. A nil options is equivalent to the zero value.
+type SlogHandlerOptions struct {
+	// ProjectID is the Google Cloud project of the traces.
+	// If empty, it is detected from GOOGLE_CLOUD_PROJECT, GCP_PROJECT or GCLOUD_PROJECT.
+	ProjectID string
+}
+
+// slogHandler is a slog.Handler that adds the trace correlation fields of Cloud Logging to records.
+type slogHandler struct {
+	next      slog.Handler
+	projectID string
+
+	// groups opened by WithGroup are kept here instead of next, since the fields must be at the top level
+	groups []slogGroup
+}
+
+type slogGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewSlogHandler creates a slog.Handler that adds the fields to correlate log entries with the span
+// carried by the context of each record, then passes the record to next, which is expected to write JSON.
+// The trace field is omitted if the project ID is neither configured nor detected,
+// since Cloud Logging requires it in the form of projects/<id>/traces/<trace id>.
+func NewSlogHandler(next slog.Handler, opts *SlogHandlerOptions) slog.Handler {
+	h := &slogHandler{next: next}
+	if opts != nil {
+		h.projectID = opts.ProjectID
+	}
+	for _, env := range projectIDEnvs {
+		if h.projectID != "" {
+			break
+		}
+		h.projectID = os.Getenv(env)
+	}
+	return h
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the trace correlation fields to the record, then passes it to the next handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() && len(h.groups) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	if sc.IsValid() {
+		record.AddAttrs(h.correlationAttrs(sc)...)
+	}
+	record.AddAttrs(h.groupedAttrs(r)...)
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a new handler with attrs added to all the records, under the current groups.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	if len(h.groups) == 0 {
+		h2.next = h.next.WithAttrs(attrs)
+		return &h2
+	}
+	h2.groups = slices.Clone(h.groups)
+	last := &h2.groups[len(h2.groups)-1]
+	last.attrs = append(slices.Clip(last.attrs), attrs...)
+	return &h2
+}
+
+// WithGroup returns a new handler that qualifies the following attributes with name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), slogGroup{name: name})
+	return &h2
+}
+
+func (h *slogHandler) correlationAttrs(sc trace.SpanContext) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3)
+	if h.projectID != "" {
+		attrs = append(attrs, slog.String(traceKey, "projects/"+h.projectID+"/traces/"+sc.TraceID().String()))
+	}
+	return append(attrs,
+		slog.String(spanIDKey, sc.SpanID().String()),
+		slog.Bool(traceSampledKey, sc.IsSampled()),
+	)
+}
+
+// groupedAttrs returns the attributes of r nested in the groups opened by WithGroup, along with the attributes of each group.
+func (h *slogHandler) groupedAttrs(r slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		members := append(slices.Clip(g.attrs), attrs...)
+		attrs = []slog.Attr{{Key: g.name, Value: slog.GroupValue(members...)}}
+	}
+	return attrs
+}
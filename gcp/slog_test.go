@@ -0,0 +1,101 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestLogger(tb testing.TB, opts *SlogHandlerOptions) (*slog.Logger, func() map[string]any) {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil), opts))
+	return logger, func() map[string]any {
+		tb.Helper()
+		var entry map[string]any
+		require.NoError(tb, json.Unmarshal(buf.Bytes(), &entry))
+		buf.Reset()
+		return entry
+	}
+}
+
+func newTestContext(flags trace.TraceFlags) context.Context {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03, 0x04},
+		TraceFlags: flags,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestSlogHandler(t *testing.T) {
+	logger, lastEntry := newTestLogger(t, &SlogHandlerOptions{ProjectID: "my-project"})
+
+	logger.InfoContext(newTestContext(trace.FlagsSampled), "sampled")
+	entry := lastEntry()
+	assert.Equal(t, "projects/my-project/traces/01020000000000000000000000000000", entry[traceKey])
+	assert.Equal(t, "0304000000000000", entry[spanIDKey])
+	assert.Equal(t, true, entry[traceSampledKey])
+
+	logger.InfoContext(newTestContext(0), "not sampled")
+	entry = lastEntry()
+	assert.Equal(t, false, entry[traceSampledKey])
+
+	logger.InfoContext(context.Background(), "no span")
+	entry = lastEntry()
+	assert.Equal(t, "no span", entry[slog.MessageKey])
+	assert.NotContains(t, entry, traceKey)
+	assert.NotContains(t, entry, spanIDKey)
+	assert.NotContains(t, entry, traceSampledKey)
+}
+
+func TestSlogHandler__WithGroup(t *testing.T) {
+	logger, lastEntry := newTestLogger(t, &SlogHandlerOptions{ProjectID: "my-project"})
+
+	logger.With("service", "api").
+		WithGroup("req").With("id", 1).
+		WithGroup("inner").
+		InfoContext(newTestContext(trace.FlagsSampled), "grouped", "k", 2)
+	entry := lastEntry()
+	assert.Equal(t, "api", entry["service"])
+	assert.Equal(t, map[string]any{
+		"id":    float64(1),
+		"inner": map[string]any{"k": float64(2)},
+	}, entry["req"])
+	assert.Equal(t, "projects/my-project/traces/01020000000000000000000000000000", entry[traceKey])
+	assert.Equal(t, "0304000000000000", entry[spanIDKey])
+}
+
+func TestSlogHandler__ProjectID(t *testing.T) {
+	cases := []struct {
+		name string
+		opts *SlogHandlerOptions
+		env  string
+		want any
+	}{
+		{"configured", &SlogHandlerOptions{ProjectID: "configured"}, "detected", "projects/configured/traces/01020000000000000000000000000000"},
+		{"detected", nil, "detected", "projects/detected/traces/01020000000000000000000000000000"},
+		{"unknown", nil, "", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, env := range projectIDEnvs {
+				t.Setenv(env, "")
+			}
+			t.Setenv("GOOGLE_CLOUD_PROJECT", c.env)
+			logger, lastEntry := newTestLogger(t, c.opts)
+
+			logger.InfoContext(newTestContext(trace.FlagsSampled), "test")
+			entry := lastEntry()
+			assert.Equal(t, c.want, entry[traceKey])
+			assert.Equal(t, "0304000000000000", entry[spanIDKey])
+		})
+	}
+}
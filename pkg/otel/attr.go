@@ -1,10 +1,17 @@
 package otel
 
 import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -13,43 +20,86 @@ type Marshaler interface {
 	MarshalOtelAttributes() ([]attribute.KeyValue, error)
 }
 
+// MarshalOtelAttributes converts v into attributes with the default Encoder.
 func MarshalOtelAttributes(v interface{}) ([]attribute.KeyValue, error) {
+	return DefaultEncoder().Marshal(v)
+}
+
+// MarshalOtelAttributesWithKey converts v into attributes under key with the default Encoder.
+func MarshalOtelAttributesWithKey(key string, v interface{}) ([]attribute.KeyValue, error) {
+	return DefaultEncoder().MarshalWithKey(key, v)
+}
+
+// MarshalWithKey converts v into attributes under key: a scalar or a slice becomes a single attribute of the key itself,
+// and a struct or a map becomes attributes with the key as the prefix (e.g. key.field).
+func (e *Encoder) MarshalWithKey(key string, v interface{}) ([]attribute.KeyValue, error) {
 	if v == nil {
 		return []attribute.KeyValue{}, nil
 	}
-	if m, ok := v.(Marshaler); ok {
-		return m.MarshalOtelAttributes()
+	return e.marshalField(structFiled{
+		attributeName:   key,
+		attributePrefix: key + ".",
+	}, reflect.ValueOf(v))
+}
+
+// Marshal converts v into attributes according to the configuration of the Encoder.
+func (e *Encoder) Marshal(v interface{}) ([]attribute.KeyValue, error) {
+	if v == nil {
+		return []attribute.KeyValue{}, nil
 	}
 	rv := reflect.ValueOf(v)
-	return marshalOtelAttributes(rv)
+	if m, ok := asMarshaler(rv); ok {
+		return m.MarshalOtelAttributes()
+	}
+	if sv, ok := asSlogValue(rv); ok {
+		if sv = sv.Resolve(); sv.Kind() == slog.KindGroup {
+			return e.marshalSlogGroup(sv.Group())
+		}
+	}
+	return e.marshalOtelAttributes(rv)
+}
+
+// asMarshaler returns the Marshaler implemented by v, or by its pointer receiver if v is addressable as encoding/json does.
+// Non-addressable values are never copied, so that a pointer method can delegate to MarshalOtelAttributes(*v).
+func asMarshaler(v reflect.Value) (Marshaler, bool) {
+	switch {
+	case (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil():
+		return nil, false
+	case v.Type().Implements(marshalerType):
+		return v.Interface().(Marshaler), true
+	case v.CanAddr() && reflect.PointerTo(v.Type()).Implements(marshalerType):
+		return v.Addr().Interface().(Marshaler), true
+	default:
+		return nil, false
+	}
 }
 
-func marshalOtelAttributes(rv reflect.Value) ([]attribute.KeyValue, error) {
+func (e *Encoder) marshalOtelAttributes(rv reflect.Value) ([]attribute.KeyValue, error) {
 	if !rv.IsValid() {
 		return []attribute.KeyValue{}, nil
 	}
 
 	switch rv.Kind() {
 	case reflect.Struct:
-		return marshalStruct(rv)
+		return e.marshalStruct(rv)
 	case reflect.Ptr:
 		if rv.IsNil() {
 			return []attribute.KeyValue{}, nil
 		}
-		return marshalOtelAttributes(rv.Elem())
+		return e.marshalOtelAttributes(rv.Elem())
 	case reflect.Interface:
-		return marshalOtelAttributes(rv.Elem())
+		return e.marshalOtelAttributes(rv.Elem())
 	case reflect.Map:
 		if rv.IsNil() {
 			return []attribute.KeyValue{}, nil
 		}
-		return marshalMap(rv)
+		return e.marshalMap(rv)
 	default:
 		return nil, fmt.Errorf("unsupported type %s", rv.Type())
 	}
 }
 
-func marshalMap(rv reflect.Value) ([]attribute.KeyValue, error) {
+func (e *Encoder) marshalMap(rv reflect.Value) ([]attribute.KeyValue, error) {
 	keys := rv.MapKeys()
 	if len(keys) == 0 {
 		return []attribute.KeyValue{}, nil
@@ -59,127 +109,29 @@ func marshalMap(rv reflect.Value) ([]attribute.KeyValue, error) {
 	}
 	attrs := make([]attribute.KeyValue, 0, len(keys))
 	for index, key := range keys {
-		mv := rv.MapIndex(key)
+		mv := reflect.ValueOf(rv.MapIndex(key).Interface())
+		if !mv.IsValid() {
+			continue
+		}
+		// map values are copied to be addressable, so that the methods with pointer receivers are available
+		addressable := reflect.New(mv.Type()).Elem()
+		addressable.Set(mv)
+		mv = addressable
 		keyString := key.String()
-		switch value := mv.Interface().(type) {
-		case bool:
-			attrs = append(attrs, attribute.Bool(keyString, value))
-		case int:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case int8:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case int16:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case int32:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case int64:
-			attrs = append(attrs, attribute.Int64(keyString, value))
-		case uint:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case uint8:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case uint16:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case uint32:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case uint64:
-			attrs = append(attrs, attribute.Int64(keyString, (int64)(value)))
-		case float32:
-			attrs = append(attrs, attribute.Float64(keyString, (float64)(value)))
-		case float64:
-			attrs = append(attrs, attribute.Float64(keyString, value))
-		case string:
-			attrs = append(attrs, attribute.String(keyString, value))
-		case []bool:
-			attrs = append(attrs, attribute.BoolSlice(keyString, value))
-		case []int:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []int8:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []int16:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []int32:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []int64:
-			attrs = append(attrs, attribute.Int64Slice(keyString, value))
-		case []uint:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []uint8:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []uint16:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []uint32:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []uint64:
-			s := make([]int64, len(value))
-			for i, v := range value {
-				s[i] = int64(v)
-			}
-			attrs = append(attrs, attribute.Int64Slice(keyString, s))
-		case []float32:
-			s := make([]float64, len(value))
-			for i, v := range value {
-				s[i] = float64(v)
-			}
-			attrs = append(attrs, attribute.Float64Slice(keyString, s))
-		case []float64:
-			attrs = append(attrs, attribute.Float64Slice(keyString, value))
-		case []string:
-			attrs = append(attrs, attribute.StringSlice(keyString, value))
-		default:
-			if mv.Type().ConvertibleTo(timeType) {
-				t := mv.Convert(timeType).Interface().(time.Time)
-				attrs = append(attrs, attribute.String(keyString, t.Format(time.RFC3339Nano)))
-				continue
-			}
-
-			kvs, err := marshalField(structFiled{
-				attributeName:   keyString,
-				filedIndex:      index,
-				attributePrefix: keyString + ".",
-			}, reflect.ValueOf(value))
-			if err != nil {
-				return nil, err
-			}
-			attrs = append(attrs, kvs...)
+		kvs, err := e.marshalField(structFiled{
+			attributeName:   keyString,
+			filedIndex:      index,
+			attributePrefix: keyString + ".",
+		}, mv)
+		if err != nil {
+			return nil, err
 		}
+		attrs = append(attrs, kvs...)
 	}
 	return attrs, nil
 }
 
-func marshalStruct(rv reflect.Value) ([]attribute.KeyValue, error) {
+func (e *Encoder) marshalStruct(rv reflect.Value) ([]attribute.KeyValue, error) {
 	t := rv.Type()
 	fields := getStructFields(t)
 	kvs := make([]attribute.KeyValue, 0, len(fields))
@@ -188,7 +140,10 @@ func marshalStruct(rv reflect.Value) ([]attribute.KeyValue, error) {
 		if f.omitEmpty && isEmptyValue(fv) {
 			continue
 		}
-		filedValue, err := marshalField(f, fv)
+		if f.omitZero && isZeroValue(fv) {
+			continue
+		}
+		filedValue, err := e.marshalField(f, fv)
 		if err != nil {
 			return nil, err
 		}
@@ -197,7 +152,33 @@ func marshalStruct(rv reflect.Value) ([]attribute.KeyValue, error) {
 	return kvs, nil
 }
 
-func marshalField(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+func (e *Encoder) marshalField(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	if fn, ok := e.lookupTypeEncoder(fv.Type()); ok && !(fv.Kind() == reflect.Ptr && fv.IsNil()) {
+		return fn(f.attributeName, fv), nil
+	}
+	if m, ok := asMarshaler(fv); ok {
+		attrs, err := m.MarshalOtelAttributes()
+		if err != nil {
+			return []attribute.KeyValue{}, err
+		}
+		return f.prefixed(attrs), nil
+	}
+	if sv, ok := asSlogValue(fv); ok {
+		return e.marshalSlogValue(f, sv)
+	}
+	if v, ok, err := driverValue(fv); ok {
+		if err != nil || v == nil {
+			return nil, err
+		}
+		if bs, ok := v.([]byte); ok {
+			return e.marshalString(f, string(bs)), nil
+		}
+		return e.marshalField(f, reflect.ValueOf(v))
+	}
+	// expanded slices are coerced element by element in marshalExpanded
+	if f.coerce != "" && !(f.expand && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array)) {
+		return e.marshalCoerced(f, fv)
+	}
 	switch fv.Kind() {
 	case reflect.Bool:
 		return []attribute.KeyValue{attribute.Bool(f.attributeName, fv.Bool())}, nil
@@ -208,58 +189,141 @@ func marshalField(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error)
 	case reflect.Float32, reflect.Float64:
 		return []attribute.KeyValue{attribute.Float64(f.attributeName, fv.Float())}, nil
 	case reflect.String:
-		return []attribute.KeyValue{attribute.String(f.attributeName, fv.String())}, nil
+		return e.marshalString(f, fv.String()), nil
 	case reflect.Slice, reflect.Array:
-		return marshalSlice(f, fv)
+		return e.marshalSlice(f, fv)
 	case reflect.Struct:
 		// convert time.Time to string
 		if fv.Type().ConvertibleTo(timeType) {
 			t := fv.Convert(timeType).Interface().(time.Time)
 			return []attribute.KeyValue{attribute.String(f.attributeName, t.Format(time.RFC3339Nano))}, nil
 		}
-		return f.marshalNested(fv)
+		return e.marshalNested(f, fv)
 
 	case reflect.Ptr:
 		if fv.IsNil() {
 			return nil, nil
 		}
-		return marshalField(f, fv.Elem())
+		return e.marshalField(f, fv.Elem())
 
 	case reflect.Map:
-		return f.marshalNested(fv)
+		return e.marshalNested(f, fv)
 
 	default:
 		bs, err := json.Marshal(fv.Interface())
 		if err != nil {
 			return []attribute.KeyValue{}, err
 		}
-		return []attribute.KeyValue{attribute.String(f.attributeName, string(bs))}, nil
+		return e.marshalString(f, string(bs)), nil
 	}
 }
 
-func (s structFiled) marshalNested(fv reflect.Value) ([]attribute.KeyValue, error) {
-	attrs, err := MarshalOtelAttributes(fv.Interface())
+func (e *Encoder) marshalNested(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	attrs, err := e.Marshal(fv.Interface())
 	if err != nil {
 		return []attribute.KeyValue{}, err
 	}
-	for i := range attrs {
-		attrs[i].Key = attribute.Key(s.attributePrefix) + attrs[i].Key
+	return f.prefixed(attrs), nil
+}
+
+// driverValue unwraps nullable types such as sql.NullString and sql.Null[T] through driver.Valuer,
+// which returns nil if the value is not valid. It reports false if fv is not a struct with a Valid field
+// implementing driver.Valuer, so that other column types such as JSON documents are marshaled by their fields.
+func driverValue(fv reflect.Value) (driver.Value, bool, error) {
+	if fv.Kind() != reflect.Struct {
+		return nil, false, nil
+	}
+	t := fv.Type()
+	if valid, ok := t.FieldByName("Valid"); !ok || valid.Type.Kind() != reflect.Bool {
+		return nil, false, nil
 	}
-	return attrs, nil
+	switch {
+	case t.Implements(valuerType):
+	case fv.CanAddr() && reflect.PointerTo(t).Implements(valuerType):
+		fv = fv.Addr()
+	default:
+		return nil, false, nil
+	}
+	v, err := fv.Interface().(driver.Valuer).Value()
+	return v, true, err
 }
 
-func marshalSlice(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+// marshalString emits s cut to the length limit of the field, followed by a truncation marker if it was cut.
+func (e *Encoder) marshalString(f structFiled, s string) []attribute.KeyValue {
+	s, truncated := truncateString(s, e.maxLenOf(f))
+	return f.withTruncated([]attribute.KeyValue{attribute.String(f.attributeName, s)}, truncated)
+}
+
+func (e *Encoder) marshalSlice(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	if f.expand {
+		return e.marshalExpanded(f, fv)
+	}
+	if f.columnar {
+		if attrs, ok, err := e.marshalColumnar(f, fv); ok || err != nil {
+			return attrs, err
+		}
+	}
+
+	if fv.Type().Elem().Kind() == reflect.Ptr {
+		if e.nilPolicyOf(f) == NilPlaceholder {
+			return e.marshalWithPlaceholders(f, fv)
+		}
+		return e.marshalSlice(f, e.derefElements(f, fv))
+	}
+
+	// byte slice types with their own text representation such as net.IP are not encoded as bytes
+	if fv.Type().Elem().Kind() == reflect.Uint8 && canStringifyText(fv) {
+		s, err := stringifyText(fv)
+		if err != nil {
+			return nil, err
+		}
+		return e.marshalString(f, s), nil
+	}
+
+	fv, truncated := e.truncateItems(f, fv)
+	// named byte types with their own text representation are stringified element by element below
+	if fv.Type().Elem().Kind() == reflect.Uint8 && !isTextElements(fv) {
+		s, err := encodeBytes(f.encoding, fv)
+		if err != nil {
+			return nil, err
+		}
+		s, cut := truncateString(s, e.maxLenOf(f))
+		return f.withTruncated([]attribute.KeyValue{attribute.String(f.attributeName, s)}, truncated || cut), nil
+	}
+
+	if isTextElements(fv) {
+		strs := make([]string, fv.Len())
+		for i := range strs {
+			s, err := stringifyText(fv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			var cut bool
+			strs[i], cut = truncateString(s, e.maxLenOf(f))
+			truncated = truncated || cut
+		}
+		return f.withTruncated([]attribute.KeyValue{attribute.StringSlice(f.attributeName, strs)}, truncated), nil
+	}
+
+	var attr attribute.KeyValue
+	var ok bool
 	switch fv.Type().Elem().Kind() {
 	case reflect.Bool:
-		return []attribute.KeyValue{attribute.BoolSlice(f.attributeName, reflectValueToSlice[bool](fv))}, nil
+		attr, ok = attribute.BoolSlice(f.attributeName, reflectValueToSlice[bool](fv)), true
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return []attribute.KeyValue{attribute.Int64Slice(f.attributeName, reflectValueToSlice[int64](fv))}, nil
+		attr, ok = attribute.Int64Slice(f.attributeName, reflectValueToSlice[int64](fv)), true
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return []attribute.KeyValue{attribute.Int64Slice(f.attributeName, reflectValueToSlice[int64](fv))}, nil
+		attr, ok = attribute.Int64Slice(f.attributeName, reflectValueToSlice[int64](fv)), true
 	case reflect.Float32, reflect.Float64:
-		return []attribute.KeyValue{attribute.Float64Slice(f.attributeName, reflectValueToSlice[float64](fv))}, nil
+		attr, ok = attribute.Float64Slice(f.attributeName, reflectValueToSlice[float64](fv)), true
 	case reflect.String:
-		return []attribute.KeyValue{attribute.StringSlice(f.attributeName, reflectValueToSlice[string](fv))}, nil
+		strs := reflectValueToSlice[string](fv)
+		for i := range strs {
+			var cut bool
+			strs[i], cut = truncateString(strs[i], e.maxLenOf(f))
+			truncated = truncated || cut
+		}
+		attr, ok = attribute.StringSlice(f.attributeName, strs), true
 	case reflect.Struct:
 		if fv.Type().Elem().ConvertibleTo(timeType) {
 			strs := make([]string, fv.Len())
@@ -267,16 +331,255 @@ func marshalSlice(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error)
 				t := fv.Index(i).Convert(timeType).Interface().(time.Time)
 				strs[i] = t.Format(time.RFC3339Nano)
 			}
-			return []attribute.KeyValue{attribute.StringSlice(f.attributeName, strs)}, nil
+			attr, ok = attribute.StringSlice(f.attributeName, strs), true
 		}
 		// There is no choice but to provide only stringification because composite arrays are not supported at the OpenTelemetry protocol level.
 	}
+	if ok {
+		return f.withTruncated([]attribute.KeyValue{attr}, truncated), nil
+	}
+
 	bs, err := json.Marshal(fv.Interface())
 	if err != nil {
 		return []attribute.KeyValue{}, err
 	}
+	str, cut := truncateString(string(bs), e.maxLenOf(f))
+	return f.withTruncated([]attribute.KeyValue{attribute.String(f.attributeName, str)}, truncated || cut), nil
+}
+
+// derefElements dereferences the pointer elements of a slice, dropping or zeroing nil ones according to the nil policy.
+// NilPlaceholder also zeroes nil elements since typed slices cannot hold the placeholder.
+func (e *Encoder) derefElements(f structFiled, fv reflect.Value) reflect.Value {
+	et := fv.Type().Elem().Elem()
+	s := reflect.MakeSlice(reflect.SliceOf(et), 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		elem := fv.Index(i)
+		if elem.IsNil() {
+			if e.nilPolicyOf(f) != NilSkip {
+				s = reflect.Append(s, reflect.Zero(et))
+			}
+			continue
+		}
+		s = reflect.Append(s, elem.Elem())
+	}
+	return s
+}
+
+// marshalWithPlaceholders stringifies the pointer elements of a slice so that nil ones can be told apart.
+func (e *Encoder) marshalWithPlaceholders(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	fv, truncated := e.truncateItems(f, fv)
+	strs := make([]string, fv.Len())
+	for i := range strs {
+		elem := fv.Index(i)
+		for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Ptr {
+			strs[i] = nilPlaceholder
+			continue
+		}
+
+		s, err := stringifyElement(elem)
+		if err != nil {
+			return nil, err
+		}
+		var cut bool
+		strs[i], cut = truncateString(s, e.maxLenOf(f))
+		truncated = truncated || cut
+	}
+	return f.withTruncated([]attribute.KeyValue{attribute.StringSlice(f.attributeName, strs)}, truncated), nil
+}
+
+// stringifyElement converts a scalar into a string, or a composite value into a JSON string.
+func stringifyElement(v reflect.Value) (string, error) {
+	if !v.Type().ConvertibleTo(timeType) && canStringifyText(v) {
+		return stringifyText(v)
+	}
+	if s, err := coerceToString(v); err == nil {
+		return s, nil
+	}
+	bs, err := json.Marshal(v.Interface())
+	return string(bs), err
+}
+
+// isTextElements reports whether the elements of a slice or array implement fmt.Stringer or encoding.TextMarshaler.
+// time.Time is excluded to be formatted in RFC3339 like the other places.
+func isTextElements(fv reflect.Value) bool {
+	et := fv.Type().Elem()
+	if et.ConvertibleTo(timeType) {
+		return false
+	}
+	if et.Implements(stringerType) || et.Implements(textMarshalerType) {
+		return true
+	}
+	// elements of a slice are always addressable, so the methods with pointer receivers are also available
+	pt := reflect.PointerTo(et)
+	return fv.Kind() == reflect.Slice && (pt.Implements(stringerType) || pt.Implements(textMarshalerType))
+}
+
+// canStringifyText reports whether v or its address implements fmt.Stringer or encoding.TextMarshaler.
+func canStringifyText(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(stringerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return v.CanAddr() && (pt.Implements(stringerType) || pt.Implements(textMarshalerType))
+}
+
+// stringifyText converts v into a string with its String or MarshalText method.
+func stringifyText(v reflect.Value) (string, error) {
+	if !v.Type().Implements(stringerType) && !v.Type().Implements(textMarshalerType) && v.CanAddr() {
+		v = v.Addr()
+	}
+	switch x := v.Interface().(type) {
+	case fmt.Stringer:
+		return x.String(), nil
+	case encoding.TextMarshaler:
+		bs, err := x.MarshalText()
+		return string(bs), err
+	default:
+		return "", fmt.Errorf("%s implements neither fmt.Stringer nor encoding.TextMarshaler", v.Type())
+	}
+}
+
+// marshalExpanded flattens the elements of a slice into indexed keys (e.g. items.0.sku, items.1.sku)
+// up to the limit of the field, and reports the total number of elements as items.count.
+// The item limit also applies if it is smaller, in which case items.truncated=true is added as other slices do,
+// and the `type` option converts each element.
+func (e *Encoder) marshalExpanded(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	n := fv.Len()
+	limit, truncated := f.expandLimit, false
+	if maxItems := e.maxItemsOf(f); maxItems > 0 && maxItems < limit {
+		limit, truncated = maxItems, n > maxItems
+	}
+	attrs := []attribute.KeyValue{attribute.Int(f.attributePrefix+"count", n)}
+	for i := 0; i < min(n, limit); i++ {
+		key := f.attributePrefix + strconv.Itoa(i)
+		kvs, err := e.marshalField(structFiled{
+			attributeName:   key,
+			filedIndex:      i,
+			attributePrefix: key + ".",
+			maxLen:          f.maxLen,
+			coerce:          f.coerce,
+			encoding:        f.encoding,
+		}, fv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, kvs...)
+	}
+	return f.withTruncated(attrs, truncated), nil
+}
+
+// marshalColumnar encodes a slice of structs as a struct of arrays (e.g. line_items.sku, line_items.qty),
+// so that each field keeps its type and the number of attributes does not depend on the length of the slice.
+// It reports false if the elements are not structs, in which case the slice is already columnar by itself.
+func (e *Encoder) marshalColumnar(f structFiled, fv reflect.Value) ([]attribute.KeyValue, bool, error) {
+	et := fv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct || et.ConvertibleTo(timeType) {
+		return nil, false, nil
+	}
+
+	n := fv.Len()
+	fields := getStructFields(et)
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for _, sf := range fields {
+		// nil rows are left as zero values to keep the columns aligned
+		column := reflect.MakeSlice(reflect.SliceOf(et.Field(sf.filedIndex).Type), n, n)
+		for i := 0; i < n; i++ {
+			elem := fv.Index(i)
+			if isPtr {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			column.Index(i).Set(elem.Field(sf.filedIndex))
+		}
 
-	return []attribute.KeyValue{attribute.String(f.attributeName, string(bs))}, nil
+		// the tag options of the element field apply to its column, except for the item limit of the rows
+		key := f.attributePrefix + sf.attributeName
+		columnField := structFiled{
+			attributeName:   key,
+			filedName:       sf.filedName,
+			filedIndex:      sf.filedIndex,
+			attributePrefix: key + ".",
+			maxLen:          sf.maxLen,
+			maxItems:        f.maxItems,
+			coerce:          sf.coerce,
+			encoding:        sf.encoding,
+			nilPolicy:       sf.nilPolicy,
+		}
+		if columnField.maxLen == 0 {
+			columnField.maxLen = f.maxLen
+		}
+		// nil pointers in the column must not be dropped either, or the values would shift to other rows
+		if e.nilPolicyOf(columnField) == NilSkip {
+			columnField.nilPolicy = "zero"
+		}
+		var (
+			kvs []attribute.KeyValue
+			err error
+		)
+		if columnField.coerce != "" {
+			kvs, err = e.marshalCoerced(columnField, column)
+		} else {
+			kvs, err = e.marshalSlice(columnField, column)
+		}
+		if err != nil {
+			return nil, true, err
+		}
+		attrs = append(attrs, kvs...)
+	}
+	return attrs, true, nil
+}
+
+// truncateItems returns the first elements of the slice or array up to the item limit of the field,
+// and whether any element was dropped.
+func (e *Encoder) truncateItems(f structFiled, fv reflect.Value) (reflect.Value, bool) {
+	limit := e.maxItemsOf(f)
+	if limit <= 0 || fv.Len() <= limit {
+		return fv, false
+	}
+	if fv.Kind() == reflect.Slice {
+		return fv.Slice(0, limit), true
+	}
+	// arrays are not always addressable, so their elements are copied instead of sliced
+	s := reflect.MakeSlice(reflect.SliceOf(fv.Type().Elem()), limit, limit)
+	reflect.Copy(s, fv)
+	return s, true
+}
+
+// encodeBytes stringifies a byte slice or array (e.g. a digest) with the encoding given by the `encoding` tag option.
+func encodeBytes(encoding string, fv reflect.Value) (string, error) {
+	bs := make([]byte, fv.Len())
+	for i := range bs {
+		bs[i] = byte(fv.Index(i).Uint())
+	}
+	switch encoding {
+	case "", "base64":
+		return base64.StdEncoding.EncodeToString(bs), nil
+	case "hex":
+		return hex.EncodeToString(bs), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding %q for %s", encoding, fv.Type())
+	}
+}
+
+// truncateString cuts s to at most n bytes without splitting a multibyte character.
+func truncateString(s string, n int) (string, bool) {
+	if n <= 0 || len(s) <= n {
+		return s, false
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
 }
 
 func reflectValueToSlice[T any](v reflect.Value) []T {
@@ -301,9 +604,31 @@ func isEmptyValue(v reflect.Value) bool {
 		return v.Float() == 0
 	case reflect.String:
 		return v.String() == ""
-	case reflect.Slice:
+	case reflect.Array, reflect.Map, reflect.Slice:
 		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
 	default:
 		return false
 	}
 }
+
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+
+// isZeroValue reports whether v is zero, preferring the IsZero method of the type (e.g. time.Time) if it has one.
+func isZeroValue(v reflect.Value) bool {
+	switch {
+	case (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil():
+		return true
+	case v.Type().Implements(isZeroerType):
+		return v.Interface().(isZeroer).IsZero()
+	case v.CanAddr() && v.Addr().Type().Implements(isZeroerType):
+		return v.Addr().Interface().(isZeroer).IsZero()
+	default:
+		return v.IsZero()
+	}
+}
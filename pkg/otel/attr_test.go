@@ -2,7 +2,12 @@ package otel
 
 import (
 	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net"
 	"slices"
 	"testing"
 	"time"
@@ -158,6 +163,48 @@ func TestMarshalOtelAttributes__WithOmitempty(t *testing.T) {
 	assertAttributes(t, want, got)
 }
 
+func TestMarshalOtelAttributes__WithOmitemptyReferences(t *testing.T) {
+	args := struct {
+		Pointer   *structNoTags          `otel:",omitempty"`
+		Map       map[string]interface{} `otel:",omitempty"`
+		Interface interface{}            `otel:",omitempty"`
+		Array     [0]int                 `otel:",omitempty"`
+	}{}
+	want := []attribute.KeyValue{}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type zeroByMethod struct {
+	Value int
+}
+
+func (z zeroByMethod) IsZero() bool {
+	return z.Value < 0
+}
+
+func TestMarshalOtelAttributes__WithOmitzero(t *testing.T) {
+	args := struct {
+		Time      time.Time    `otel:",omitzero"`
+		Struct    structNoTags `otel:",omitzero"`
+		Pointer   *int         `otel:",omitzero"`
+		Int       int          `otel:",omitzero"`
+		ByMethod  zeroByMethod `otel:",omitzero"`
+		NonZero   zeroByMethod `otel:",omitzero"`
+		OmitEmpty time.Time    `otel:",omitempty"` // structs are never empty, but they may be zero
+	}{
+		ByMethod: zeroByMethod{Value: -1},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int64("non_zero.value", 0),
+		attribute.String("omit_empty", "0001-01-01T00:00:00Z"),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
 type structWithMarshaller struct {
 	Value int
 }
@@ -198,6 +245,70 @@ func TestMarshalOtelAttributes__NestedStructWithMarshallerMember(t *testing.T) {
 	assertAttributes(t, want, got)
 }
 
+type structWithPointerMarshaller struct {
+	Value int
+}
+
+func (t *structWithPointerMarshaller) MarshalOtelAttributes() ([]attribute.KeyValue, error) {
+	return []attribute.KeyValue{
+		attribute.Int("custom", t.Value),
+	}, nil
+}
+
+type stringWithPointerMarshaller string
+
+func (s *stringWithPointerMarshaller) MarshalOtelAttributes() ([]attribute.KeyValue, error) {
+	return []attribute.KeyValue{
+		attribute.String("custom", string(*s)),
+	}, nil
+}
+
+func TestMarshalOtelAttributes__WithPointerMarshallerMembers(t *testing.T) {
+	args := &struct {
+		Nested   structWithPointerMarshaller
+		Named    stringWithPointerMarshaller
+		Map      map[string]structWithPointerMarshaller
+		Elements []structWithPointerMarshaller `otel:",expand"`
+		Nil      *structWithPointerMarshaller
+	}{
+		Nested:   structWithPointerMarshaller{Value: 1},
+		Named:    "named",
+		Map:      map[string]structWithPointerMarshaller{"value": {Value: 2}},
+		Elements: []structWithPointerMarshaller{{Value: 3}},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int("nested.custom", 1),
+		attribute.String("named.custom", "named"),
+		attribute.Int("map.value.custom", 2),
+		attribute.Int("elements.count", 1),
+		attribute.Int("elements.0.custom", 3),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type delegatingMarshaller struct {
+	ID int `otel:"id"`
+}
+
+func (d *delegatingMarshaller) MarshalOtelAttributes() ([]attribute.KeyValue, error) {
+	return MarshalOtelAttributes(*d)
+}
+
+func TestMarshalOtelAttributes__WithDelegatingPointerMarshaller(t *testing.T) {
+	want := []attribute.KeyValue{
+		attribute.Int64("id", 1),
+	}
+	got, err := MarshalOtelAttributes(&delegatingMarshaller{ID: 1})
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+
+	got, err = MarshalOtelAttributes(delegatingMarshaller{ID: 1})
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
 type structWithNameAndOmitemptyTags struct {
 	BoolValue   bool      `otel:"b,omitempty"`
 	BoolSlice   []bool    `otel:"bs,omitempty"`
@@ -311,6 +422,569 @@ func TestMarshalOtelAttributes__WithMap(t *testing.T) {
 	assertAttributes(t, want, got)
 }
 
+type lineItem struct {
+	SKU string `otel:"sku"`
+	Qty int    `otel:"qty"`
+}
+
+func TestMarshalOtelAttributes__WithExpand(t *testing.T) {
+	args := struct {
+		Items []lineItem `otel:"items,expand"`
+		Tags  []string   `otel:",expand=1"`
+		Empty []lineItem `otel:",expand"`
+		Codes []int      `otel:",expand,maxitems=1"`
+		IDs   []int      `otel:"ids,expand=2,type=string"`
+	}{
+		Items: []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}},
+		Tags:  []string{"foo", "bar"},
+		Codes: []int{1, 2, 3},
+		IDs:   []int{1, 2, 3},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int("items.count", 2),
+		attribute.String("items.0.sku", "a"),
+		attribute.Int64("items.0.qty", 1),
+		attribute.String("items.1.sku", "b"),
+		attribute.Int64("items.1.qty", 2),
+		attribute.Int("tags.count", 2),
+		attribute.String("tags.0", "foo"),
+		attribute.Int("empty.count", 0),
+		attribute.Int("codes.count", 3),
+		attribute.Int64("codes.0", 1),
+		attribute.Bool("codes.truncated", true),
+		attribute.Int("ids.count", 3),
+		attribute.String("ids.0", "1"),
+		attribute.String("ids.1", "2"),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestMarshalOtelAttributes__WithColumnar(t *testing.T) {
+	args := struct {
+		LineItems []lineItem  `otel:",columnar"`
+		Pointers  []*lineItem `otel:",columnar"`
+		Strings   []string    `otel:",columnar"`
+	}{
+		LineItems: []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}},
+		Pointers:  []*lineItem{{SKU: "a", Qty: 1}, nil},
+		Strings:   []string{"foo"},
+	}
+	want := []attribute.KeyValue{
+		attribute.StringSlice("line_items.sku", []string{"a", "b"}),
+		attribute.Int64Slice("line_items.qty", []int64{1, 2}),
+		attribute.StringSlice("pointers.sku", []string{"a", ""}),
+		attribute.Int64Slice("pointers.qty", []int64{1, 0}),
+		attribute.StringSlice("strings", []string{"foo"}),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type taggedRow struct {
+	ID   int    `otel:"id,type=string"`
+	Name string `otel:"name,maxlen=2"`
+}
+
+func TestMarshalOtelAttributes__WithColumnarTagOptions(t *testing.T) {
+	args := struct {
+		Rows []taggedRow `otel:",columnar"`
+	}{
+		Rows: []taggedRow{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}},
+	}
+	want := []attribute.KeyValue{
+		attribute.StringSlice("rows.id", []string{"1", "2"}),
+		attribute.StringSlice("rows.name", []string{"fo", "ba"}),
+		attribute.Bool("rows.name.truncated", true),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type nullableRow struct {
+	P *int `otel:"p"`
+	Q *int `otel:"q,nil=placeholder"`
+}
+
+func TestMarshalOtelAttributes__WithColumnarNilPointers(t *testing.T) {
+	one, three := 1, 3
+	args := struct {
+		Rows []nullableRow `otel:",columnar"`
+	}{
+		Rows: []nullableRow{{P: &one, Q: &one}, {}, {P: &three, Q: &three}},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int64Slice("rows.p", []int64{1, 0, 3}),
+		attribute.StringSlice("rows.q", []string{"1", "<nil>", "3"}),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+
+	got, err = NewEncoder(WithNilPolicy(NilSkip)).Marshal(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestMarshalOtelAttributes__WithTruncation(t *testing.T) {
+	args := struct {
+		Body  string    `otel:"body,maxlen=5"`
+		Multi string    `otel:"multi,maxlen=5"`
+		IDs   []int     `otel:"ids,maxitems=2"`
+		Tags  [3]string `otel:"tags,maxitems=2,maxlen=3"`
+		Short string    `otel:"short,maxlen=5"`
+	}{
+		Body:  "hello, world",
+		Multi: "こんにちは",
+		IDs:   []int{1, 2, 3},
+		Tags:  [3]string{"foo", "barbaz", "qux"},
+		Short: "hello",
+	}
+	want := []attribute.KeyValue{
+		attribute.String("body", "hello"),
+		attribute.Bool("body.truncated", true),
+		attribute.String("multi", "こ"), // a multibyte character is never split
+		attribute.Bool("multi.truncated", true),
+		attribute.Int64Slice("ids", []int64{1, 2}),
+		attribute.Bool("ids.truncated", true),
+		attribute.StringSlice("tags", []string{"foo", "bar"}),
+		attribute.Bool("tags.truncated", true),
+		attribute.String("short", "hello"),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestEncoder_Marshal__WithLimits(t *testing.T) {
+	args := struct {
+		Body   string
+		IDs    []int `otel:"ids"`
+		Items  []lineItem
+		Custom string `otel:"custom,maxlen=8"`
+	}{
+		Body:   "hello, world",
+		IDs:    []int{1, 2, 3},
+		Items:  []lineItem{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 2}},
+		Custom: "hello, world",
+	}
+	want := []attribute.KeyValue{
+		attribute.String("body", "hello"),
+		attribute.Bool("body.truncated", true),
+		attribute.Int64Slice("ids", []int64{1, 2}),
+		attribute.Bool("ids.truncated", true),
+		attribute.String("items", `[{"SK`),
+		attribute.Bool("items.truncated", true),
+		attribute.String("custom", "hello, w"),
+		attribute.Bool("custom.truncated", true),
+	}
+	got, err := NewEncoder(WithMaxLen(5), WithMaxItems(2)).Marshal(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type orderStatus int
+
+func (s orderStatus) String() string {
+	return fmt.Sprintf("status_%d", s)
+}
+
+func TestMarshalOtelAttributes__WithTypeOption(t *testing.T) {
+	orderID := int64(9007199254740993)
+	two := 2
+	args := struct {
+		OrderID  *int64      `otel:"order_id,type=string"`
+		Status   orderStatus `otel:"status,type=string"`
+		Price    float64     `otel:"price,type=int"`
+		Count    string      `otel:"count,type=int"`
+		Ratio    int         `otel:"ratio,type=float"`
+		Enabled  string      `otel:"enabled,type=bool"`
+		IDs      []int       `otel:"ids,type=string"`
+		Item     lineItem    `otel:"item,type=json"`
+		Nil      *int        `otel:"nil,type=string"`
+		Interval time.Time   `otel:"interval,type=string"`
+		Pointers []*int      `otel:"pointers,type=string,nil=zero"`
+	}{
+		OrderID:  &orderID,
+		Status:   orderStatus(2),
+		Price:    12.9,
+		Count:    "42",
+		Ratio:    3,
+		Enabled:  "true",
+		IDs:      []int{1, 2},
+		Item:     lineItem{SKU: "a", Qty: 1},
+		Pointers: []*int{&two, nil},
+	}
+	want := []attribute.KeyValue{
+		attribute.String("order_id", "9007199254740993"),
+		attribute.String("status", "status_2"),
+		attribute.Int64("price", 12),
+		attribute.Int64("count", 42),
+		attribute.Float64("ratio", 3),
+		attribute.Bool("enabled", true),
+		attribute.StringSlice("ids", []string{"1", "2"}),
+		attribute.String("item", `{"SKU":"a","Qty":1}`),
+		attribute.String("interval", "0001-01-01T00:00:00Z"),
+		attribute.StringSlice("pointers", []string{"2", "0"}),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestMarshalOtelAttributes__WithImpossibleTypeOption(t *testing.T) {
+	cases := map[string]interface{}{
+		"unparsable string": struct {
+			V string `otel:"v,type=int"`
+		}{V: "abc"},
+		"struct to string": struct {
+			V lineItem `otel:"v,type=string"`
+		}{},
+		"overflow": struct {
+			V uint64 `otel:"v,type=int"`
+		}{V: 1 << 63},
+		"unknown type": struct {
+			V int `otel:"v,type=decimal"`
+		}{},
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := MarshalOtelAttributes(args)
+			assert.Error(t, err)
+		})
+	}
+}
+
+type logLevel uint8
+
+func (l logLevel) String() string {
+	return fmt.Sprintf("level_%d", l)
+}
+
+func TestMarshalOtelAttributes__WithBytes(t *testing.T) {
+	args := struct {
+		Payload []byte     `otel:"payload"`
+		Levels  []logLevel `otel:"levels"`
+		IP      net.IP     `otel:"ip"`
+		Digest  [4]byte    `otel:"digest,encoding=hex"`
+		Capped  []byte     `otel:"capped,encoding=hex,maxitems=2"`
+		Map     map[string]interface{}
+	}{
+		Payload: []byte("hello"),
+		Levels:  []logLevel{1, 2},
+		IP:      net.IPv4(10, 0, 0, 1),
+		Digest:  [4]byte{0xde, 0xad, 0xbe, 0xef},
+		Capped:  []byte{0x01, 0x02, 0x03},
+		Map:     map[string]interface{}{"bytes": []byte("hi")},
+	}
+	want := []attribute.KeyValue{
+		attribute.String("payload", "aGVsbG8="),
+		attribute.StringSlice("levels", []string{"level_1", "level_2"}),
+		attribute.String("ip", "10.0.0.1"),
+		attribute.String("digest", "deadbeef"),
+		attribute.String("capped", "0102"),
+		attribute.Bool("capped.truncated", true),
+		attribute.String("map.bytes", "aGk="),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+
+	_, err = MarshalOtelAttributes(struct {
+		V []byte `otel:"v,encoding=base32"`
+	}{})
+	assert.Error(t, err)
+}
+
+type textID struct {
+	Value int
+}
+
+func (id *textID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("id-%d", id.Value)), nil
+}
+
+func TestMarshalOtelAttributes__WithElementTypes(t *testing.T) {
+	type status string
+	a, b, one := "a", "b", 1
+	args := struct {
+		Strings      []*string
+		Zeros        []*string `otel:",nil=zero"`
+		Placeholders []*int    `otel:",nil=placeholder"`
+		Times        []*time.Time
+		Named        []status
+		Stringers    []orderStatus
+		Texts        []textID
+	}{
+		Strings:      []*string{&a, nil, &b},
+		Zeros:        []*string{&a, nil},
+		Placeholders: []*int{&one, nil},
+		Times:        []*time.Time{{}, nil},
+		Named:        []status{"active"},
+		Stringers:    []orderStatus{1, 2},
+		Texts:        []textID{{Value: 1}},
+	}
+	want := []attribute.KeyValue{
+		attribute.StringSlice("strings", []string{"a", "b"}),
+		attribute.StringSlice("zeros", []string{"a", ""}),
+		attribute.StringSlice("placeholders", []string{"1", "<nil>"}),
+		attribute.StringSlice("times", []string{"0001-01-01T00:00:00Z"}),
+		attribute.StringSlice("named", []string{"active"}),
+		attribute.StringSlice("stringers", []string{"status_1", "status_2"}),
+		attribute.StringSlice("texts", []string{"id-1"}),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestEncoder_Marshal__WithNilPolicy(t *testing.T) {
+	one := 1
+	args := struct {
+		Ints    []*int
+		Skipped []*int `otel:",nil=skip"`
+	}{
+		Ints:    []*int{nil, &one},
+		Skipped: []*int{nil, &one},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int64Slice("ints", []int64{0, 1}),
+		attribute.Int64Slice("skipped", []int64{1}),
+	}
+	got, err := NewEncoder(WithNilPolicy(NilZero)).Marshal(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestMarshalOtelAttributes__WithNullable(t *testing.T) {
+	args := struct {
+		String      sql.NullString
+		NullString  sql.NullString
+		Int         sql.NullInt64 `otel:"int,type=string"`
+		Time        sql.NullTime
+		Generic     sql.Null[float64]
+		NullGeneric *sql.Null[float64]
+		Map         map[string]interface{}
+	}{
+		String:      sql.NullString{String: "hello", Valid: true},
+		NullString:  sql.NullString{String: "ignored"},
+		Int:         sql.NullInt64{Int64: 42, Valid: true},
+		Time:        sql.NullTime{Valid: true},
+		Generic:     sql.Null[float64]{V: 3.14, Valid: true},
+		NullGeneric: &sql.Null[float64]{},
+		Map: map[string]interface{}{
+			"bool":      sql.NullBool{Bool: true, Valid: true},
+			"null_bool": sql.NullBool{},
+		},
+	}
+	want := []attribute.KeyValue{
+		attribute.String("string", "hello"),
+		attribute.String("int", "42"),
+		attribute.String("time", "0001-01-01T00:00:00Z"),
+		attribute.Float64("generic", 3.14),
+		attribute.Bool("map.bool", true),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type jsonColumn struct {
+	Region string `otel:"region"`
+}
+
+func (c jsonColumn) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+type nullBytes struct {
+	Bytes []byte
+	Valid bool
+}
+
+func (n nullBytes) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Bytes, nil
+}
+
+func TestMarshalOtelAttributes__WithValuer(t *testing.T) {
+	args := struct {
+		Meta  jsonColumn
+		Bytes nullBytes
+	}{
+		Meta:  jsonColumn{Region: "jp"},
+		Bytes: nullBytes{Bytes: []byte("raw"), Valid: true},
+	}
+	want := []attribute.KeyValue{
+		attribute.String("meta.region", "jp"),
+		attribute.String("bytes", "raw"),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+type money struct {
+	amount   int64
+	currency string
+}
+
+func init() {
+	RegisterEncoder(func(prefix string, v money) []attribute.KeyValue {
+		return []attribute.KeyValue{
+			attribute.Int64(prefix, v.amount),
+			attribute.String(prefix+".currency", v.currency),
+		}
+	})
+}
+
+func TestMarshalOtelAttributes__WithRegisteredEncoder(t *testing.T) {
+	price := money{amount: 100, currency: "JPY"}
+	args := struct {
+		Price   money
+		Pointer *money
+		Nil     *money
+		Map     map[string]money
+	}{
+		Price:   price,
+		Pointer: &price,
+		Map:     map[string]money{"tax": {amount: 10, currency: "JPY"}},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int64("price", 100),
+		attribute.String("price.currency", "JPY"),
+		attribute.Int64("pointer", 100),
+		attribute.String("pointer.currency", "JPY"),
+		attribute.Int64("map.tax", 10),
+		attribute.String("map.tax.currency", "JPY"),
+	}
+	got, err := MarshalOtelAttributes(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestEncoder_Marshal__WithTypeEncoder(t *testing.T) {
+	enc := NewEncoder(WithTypeEncoder(func(prefix string, v money) []attribute.KeyValue {
+		return []attribute.KeyValue{attribute.String(prefix, fmt.Sprintf("%d %s", v.amount, v.currency))}
+	}))
+	args := struct {
+		Price money
+	}{
+		Price: money{amount: 100, currency: "JPY"},
+	}
+	want := []attribute.KeyValue{
+		attribute.String("price", "100 JPY"),
+	}
+	got, err := enc.Marshal(args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+}
+
+func TestMarshalOtelAttributesWithKey(t *testing.T) {
+	cases := []struct {
+		name string
+		args interface{}
+		want []attribute.KeyValue
+	}{
+		{"int", 5, []attribute.KeyValue{attribute.Int64("key", 5)}},
+		{"string", "hello", []attribute.KeyValue{attribute.String("key", "hello")}},
+		{"slice", []string{"a", "b"}, []attribute.KeyValue{attribute.StringSlice("key", []string{"a", "b"})}},
+		{"time", time.Time{}, []attribute.KeyValue{attribute.String("key", "0001-01-01T00:00:00Z")}},
+		{"struct", lineItem{SKU: "a", Qty: 1}, []attribute.KeyValue{
+			attribute.String("key.sku", "a"),
+			attribute.Int64("key.qty", 1),
+		}},
+		{"marshaller", &structWithMarshaller{Value: 200}, []attribute.KeyValue{attribute.Int("key.http.staus_code", 200)}},
+		{"nil", nil, []attribute.KeyValue{}},
+		{"typed nil", (*int)(nil), nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := MarshalOtelAttributesWithKey("key", c.args)
+			assert.NoError(t, err)
+			assertAttributes(t, c.want, got)
+		})
+	}
+}
+
+type logUser struct {
+	ID    int
+	Email string
+}
+
+func (u logUser) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", u.ID),
+		slog.Group("", slog.Bool("masked", true)),
+		slog.Attr{},
+	)
+}
+
+type logToken string
+
+func (t *logToken) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+func TestMarshalOtelAttributes__WithLogValuer(t *testing.T) {
+	args := struct {
+		User    logUser
+		Token   logToken
+		Value   slog.Value
+		Map     map[string]interface{}
+		Pointer *logUser
+	}{
+		User:  logUser{ID: 1, Email: "foo@example.com"},
+		Token: "secret",
+		Value: slog.GroupValue(
+			slog.Duration("elapsed", 1500*time.Millisecond),
+			slog.Group("db", slog.Uint64("rows", 2), slog.Any("item", lineItem{SKU: "a", Qty: 1})),
+		),
+		Map: map[string]interface{}{
+			"user": logUser{ID: 2},
+		},
+	}
+	want := []attribute.KeyValue{
+		attribute.Int64("user.id", 1),
+		attribute.Bool("user.masked", true),
+		attribute.String("token", "REDACTED"),
+		attribute.String("value.elapsed", "1.5s"),
+		attribute.Int64("value.db.rows", 2),
+		attribute.String("value.db.item.sku", "a"),
+		attribute.Int64("value.db.item.qty", 1),
+		attribute.Int64("map.user.id", 2),
+		attribute.Bool("map.user.masked", true),
+	}
+	got, err := MarshalOtelAttributes(&args)
+	assert.NoError(t, err)
+	assertAttributes(t, want, got)
+
+	got, err = MarshalOtelAttributes(logUser{ID: 3})
+	assert.NoError(t, err)
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.Int64("id", 3),
+		attribute.Bool("masked", true),
+	}, got)
+}
+
+func TestEncoder_CanMarshal(t *testing.T) {
+	enc := DefaultEncoder()
+	assert.True(t, enc.CanMarshal(structWithMarshaller{}))
+	assert.True(t, enc.CanMarshal(&structWithPointerMarshaller{}))
+	assert.False(t, enc.CanMarshal(structWithPointerMarshaller{}))
+	assert.True(t, enc.CanMarshal(&money{}))
+	assert.True(t, enc.CanMarshal(logUser{}))
+	assert.True(t, enc.CanMarshal(slog.Value{}))
+	assert.False(t, enc.CanMarshal(lineItem{}))
+	assert.False(t, enc.CanMarshal((*lineItem)(nil)))
+	assert.False(t, enc.CanMarshal(nil))
+}
+
 func assertAttributes(tb testing.TB, want, got []attribute.KeyValue, msgAndArgs ...interface{}) bool {
 	tb.Helper()
 
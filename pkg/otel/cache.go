@@ -1,6 +1,10 @@
 package otel
 
 import (
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"log/slog"
 	"reflect"
 	"sync"
 	"time"
@@ -30,4 +34,12 @@ func (c *cache[T]) set(t reflect.Type, v T) {
 	c.mu.Unlock()
 }
 
-var timeType = reflect.TypeOf(time.Time{})
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	valuerType        = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	slogValueType     = reflect.TypeOf(slog.Value{})
+	logValuerType     = reflect.TypeOf((*slog.LogValuer)(nil)).Elem()
+)
@@ -0,0 +1,216 @@
+package otel
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Values of the `type` tag option
+const (
+	coerceString = "string"
+	coerceInt    = "int"
+	coerceFloat  = "float"
+	coerceBool   = "bool"
+	coerceJSON   = "json"
+)
+
+// marshalCoerced emits the value of a field with the `type` tag option as the specified attribute type.
+func (e *Encoder) marshalCoerced(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil, nil
+		}
+		fv = fv.Elem()
+	}
+
+	if f.coerce == coerceJSON {
+		bs, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return []attribute.KeyValue{}, err
+		}
+		return e.marshalString(f, string(bs)), nil
+	}
+	if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+		return e.marshalCoercedSlice(f, fv)
+	}
+
+	switch f.coerce {
+	case coerceString:
+		s, err := coerceToString(fv)
+		if err != nil {
+			return nil, f.coerceError(fv, err)
+		}
+		return e.marshalString(f, s), nil
+	case coerceInt:
+		i, err := coerceToInt(fv)
+		if err != nil {
+			return nil, f.coerceError(fv, err)
+		}
+		return []attribute.KeyValue{attribute.Int64(f.attributeName, i)}, nil
+	case coerceFloat:
+		v, err := coerceToFloat(fv)
+		if err != nil {
+			return nil, f.coerceError(fv, err)
+		}
+		return []attribute.KeyValue{attribute.Float64(f.attributeName, v)}, nil
+	case coerceBool:
+		b, err := coerceToBool(fv)
+		if err != nil {
+			return nil, f.coerceError(fv, err)
+		}
+		return []attribute.KeyValue{attribute.Bool(f.attributeName, b)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type option %q of %s", f.coerce, f.attributeName)
+	}
+}
+
+// marshalCoercedSlice converts each element of a slice or array into the specified type.
+func (e *Encoder) marshalCoercedSlice(f structFiled, fv reflect.Value) ([]attribute.KeyValue, error) {
+	if fv.Type().Elem().Kind() == reflect.Ptr {
+		if f.coerce == coerceString && e.nilPolicyOf(f) == NilPlaceholder {
+			return e.marshalWithPlaceholders(f, fv)
+		}
+		fv = e.derefElements(f, fv)
+	}
+	fv, truncated := e.truncateItems(f, fv)
+	n := fv.Len()
+	var attr attribute.KeyValue
+	switch f.coerce {
+	case coerceString:
+		strs := make([]string, n)
+		for i := range strs {
+			s, err := coerceToString(fv.Index(i))
+			if err != nil {
+				return nil, f.coerceError(fv, err)
+			}
+			var cut bool
+			strs[i], cut = truncateString(s, e.maxLenOf(f))
+			truncated = truncated || cut
+		}
+		attr = attribute.StringSlice(f.attributeName, strs)
+	case coerceInt:
+		ints := make([]int64, n)
+		for i := range ints {
+			v, err := coerceToInt(fv.Index(i))
+			if err != nil {
+				return nil, f.coerceError(fv, err)
+			}
+			ints[i] = v
+		}
+		attr = attribute.Int64Slice(f.attributeName, ints)
+	case coerceFloat:
+		floats := make([]float64, n)
+		for i := range floats {
+			v, err := coerceToFloat(fv.Index(i))
+			if err != nil {
+				return nil, f.coerceError(fv, err)
+			}
+			floats[i] = v
+		}
+		attr = attribute.Float64Slice(f.attributeName, floats)
+	case coerceBool:
+		bools := make([]bool, n)
+		for i := range bools {
+			v, err := coerceToBool(fv.Index(i))
+			if err != nil {
+				return nil, f.coerceError(fv, err)
+			}
+			bools[i] = v
+		}
+		attr = attribute.BoolSlice(f.attributeName, bools)
+	default:
+		return nil, fmt.Errorf("unsupported type option %q of %s", f.coerce, f.attributeName)
+	}
+	return f.withTruncated([]attribute.KeyValue{attr}, truncated), nil
+}
+
+func (s structFiled) coerceError(fv reflect.Value, err error) error {
+	return fmt.Errorf("cannot coerce %s of type %s to %s: %w", s.attributeName, fv.Type(), s.coerce, err)
+}
+
+func coerceToString(v reflect.Value) (string, error) {
+	if v.Type().ConvertibleTo(timeType) {
+		return v.Convert(timeType).Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+	if v.Type().Implements(stringerType) {
+		return v.Interface().(fmt.Stringer).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("%s is not a scalar (use type=json instead)", v.Kind())
+	}
+}
+
+func coerceToInt(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%g is out of the range of int64", f)
+		}
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(v.String(), 10, 64)
+	default:
+		return 0, fmt.Errorf("%s cannot be converted to an integer", v.Kind())
+	}
+}
+
+func coerceToFloat(v reflect.Value) (float64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(v.String(), 64)
+	default:
+		return 0, fmt.Errorf("%s cannot be converted to a float", v.Kind())
+	}
+}
+
+func coerceToBool(v reflect.Value) (bool, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() != 0, nil
+	case reflect.String:
+		return strconv.ParseBool(v.String())
+	default:
+		return false, fmt.Errorf("%s cannot be converted to a bool", v.Kind())
+	}
+}
@@ -0,0 +1,168 @@
+package otel
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Encoder marshals values into attributes according to its own configuration.
+// The zero value is ready to use and applies no limits.
+type Encoder struct {
+	maxLen    int
+	maxItems  int
+	nilPolicy NilPolicy
+
+	typeEncoders map[reflect.Type]typeEncoder
+}
+
+// NilPolicy decides how nil elements of slices such as []*string are encoded.
+type NilPolicy int
+
+const (
+	// NilSkip drops nil elements.
+	NilSkip NilPolicy = iota
+	// NilZero replaces nil elements with the zero value of the element type.
+	NilZero
+	// NilPlaceholder encodes all the elements as strings, with "<nil>" for nil elements.
+	NilPlaceholder
+)
+
+const nilPlaceholder = "<nil>"
+
+// EncoderOption configures an Encoder.
+type EncoderOption func(*Encoder)
+
+// WithMaxLen limits the length of string values in bytes, unless the field has the `maxlen` tag option.
+func WithMaxLen(n int) EncoderOption {
+	return func(e *Encoder) {
+		e.maxLen = n
+	}
+}
+
+// WithMaxItems limits the number of elements of slice values, unless the field has the `maxitems` tag option.
+func WithMaxItems(n int) EncoderOption {
+	return func(e *Encoder) {
+		e.maxItems = n
+	}
+}
+
+// WithNilPolicy sets how nil elements of slices are encoded, unless the field has the `nil` tag option.
+func WithNilPolicy(p NilPolicy) EncoderOption {
+	return func(e *Encoder) {
+		e.nilPolicy = p
+	}
+}
+
+// WithTypeEncoder registers fn to encode values of type T only for the Encoder, taking precedence over RegisterEncoder.
+// See RegisterEncoder for the details of fn.
+func WithTypeEncoder[T any](fn func(prefix string, v T) []attribute.KeyValue) EncoderOption {
+	return func(e *Encoder) {
+		if e.typeEncoders == nil {
+			e.typeEncoders = make(map[reflect.Type]typeEncoder)
+		}
+		e.typeEncoders[reflect.TypeFor[T]()] = newTypeEncoder(fn)
+	}
+}
+
+// NewEncoder creates a new Encoder
+func NewEncoder(opts ...EncoderOption) *Encoder {
+	e := &Encoder{}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var defaultEncoder atomic.Pointer[Encoder]
+
+func init() {
+	defaultEncoder.Store(NewEncoder())
+}
+
+// DefaultEncoder returns the Encoder used by MarshalOtelAttributes.
+func DefaultEncoder() *Encoder {
+	return defaultEncoder.Load()
+}
+
+// SetDefaultEncoder replaces the Encoder used by MarshalOtelAttributes. Passing nil restores the initial one.
+func SetDefaultEncoder(e *Encoder) {
+	if e == nil {
+		e = NewEncoder()
+	}
+	defaultEncoder.Store(e)
+}
+
+// typeEncoder is a type-erased function registered with RegisterEncoder or WithTypeEncoder.
+type typeEncoder func(prefix string, v reflect.Value) []attribute.KeyValue
+
+var globalTypeEncoders = newCache[typeEncoder]()
+
+// RegisterEncoder registers fn to encode values of the concrete type T for all encoders.
+// This is useful for types from other modules (e.g. decimal.Decimal), which cannot implement Marshaler.
+// fn receives the attribute key of the value as prefix, and may return the key itself or keys under it such as prefix+".currency".
+// Libraries should prefer WithTypeEncoder to avoid colliding with other registrations.
+func RegisterEncoder[T any](fn func(prefix string, v T) []attribute.KeyValue) {
+	globalTypeEncoders.set(reflect.TypeFor[T](), newTypeEncoder(fn))
+}
+
+func newTypeEncoder[T any](fn func(prefix string, v T) []attribute.KeyValue) typeEncoder {
+	return func(prefix string, v reflect.Value) []attribute.KeyValue {
+		return fn(prefix, v.Interface().(T))
+	}
+}
+
+// CanMarshal reports whether v has its own representation as attributes, i.e. it implements Marshaler or slog.LogValuer
+// or an encoder is registered for its type, rather than being marshaled by reflection.
+func (e *Encoder) CanMarshal(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() {
+		t := rv.Type()
+		if _, ok := e.lookupTypeEncoder(t); ok {
+			return true
+		}
+		if t.Implements(marshalerType) || t == slogValueType || t.Implements(logValuerType) {
+			return true
+		}
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return false
+}
+
+func (e *Encoder) lookupTypeEncoder(t reflect.Type) (typeEncoder, bool) {
+	if fn, ok := e.typeEncoders[t]; ok {
+		return fn, true
+	}
+	return globalTypeEncoders.get(t)
+}
+
+func (e *Encoder) maxLenOf(f structFiled) int {
+	if f.maxLen > 0 {
+		return f.maxLen
+	}
+	return e.maxLen
+}
+
+func (e *Encoder) maxItemsOf(f structFiled) int {
+	if f.maxItems > 0 {
+		return f.maxItems
+	}
+	return e.maxItems
+}
+
+func (e *Encoder) nilPolicyOf(f structFiled) NilPolicy {
+	switch f.nilPolicy {
+	case "skip":
+		return NilSkip
+	case "zero":
+		return NilZero
+	case "placeholder":
+		return NilPlaceholder
+	default:
+		return e.nilPolicy
+	}
+}
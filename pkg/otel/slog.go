@@ -0,0 +1,93 @@
+package otel
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// asSlogValue returns v as a slog.Value if it is a slog.Value or implements slog.LogValuer,
+// with a pointer receiver only if v is addressable like asMarshaler. The value is not resolved yet.
+func asSlogValue(v reflect.Value) (slog.Value, bool) {
+	switch {
+	case (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil():
+		return slog.Value{}, false
+	case v.Type() == slogValueType:
+		return v.Interface().(slog.Value), true
+	case v.Type().Implements(logValuerType):
+		return slog.AnyValue(v.Interface()), true
+	case v.CanAddr() && reflect.PointerTo(v.Type()).Implements(logValuerType):
+		return slog.AnyValue(v.Addr().Interface()), true
+	default:
+		return slog.Value{}, false
+	}
+}
+
+// marshalSlogValue emits v resolved with slog.LogValuer: a group becomes attributes prefixed with the key of the field,
+// and the other kinds are emitted like the corresponding Go types, except that durations are strings such as "1.5s"
+// and errors are their messages unless they have their own representation.
+func (e *Encoder) marshalSlogValue(f structFiled, v slog.Value) ([]attribute.KeyValue, error) {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindBool:
+		return []attribute.KeyValue{attribute.Bool(f.attributeName, v.Bool())}, nil
+	case slog.KindInt64:
+		return []attribute.KeyValue{attribute.Int64(f.attributeName, v.Int64())}, nil
+	case slog.KindUint64:
+		return []attribute.KeyValue{attribute.Int64(f.attributeName, int64(v.Uint64()))}, nil
+	case slog.KindFloat64:
+		return []attribute.KeyValue{attribute.Float64(f.attributeName, v.Float64())}, nil
+	case slog.KindString:
+		return e.marshalString(f, v.String()), nil
+	case slog.KindDuration:
+		return []attribute.KeyValue{attribute.String(f.attributeName, v.Duration().String())}, nil
+	case slog.KindTime:
+		return []attribute.KeyValue{attribute.String(f.attributeName, v.Time().Format(time.RFC3339Nano))}, nil
+	case slog.KindGroup:
+		attrs, err := e.marshalSlogGroup(v.Group())
+		if err != nil {
+			return nil, err
+		}
+		return f.prefixed(attrs), nil
+	default:
+		if v.Any() == nil {
+			return nil, nil
+		}
+		// errors usually have no exported fields, so they are emitted as their messages like slog handlers do
+		if err, ok := v.Any().(error); ok && !e.CanMarshal(err) {
+			return e.marshalString(f, err.Error()), nil
+		}
+		return e.marshalField(f, reflect.ValueOf(v.Any()))
+	}
+}
+
+// marshalSlogGroup emits the members of a group without a prefix, inlining groups with empty keys
+// and dropping empty attributes as slog handlers do.
+func (e *Encoder) marshalSlogGroup(group []slog.Attr) ([]attribute.KeyValue, error) {
+	attrs := make([]attribute.KeyValue, 0, len(group))
+	for _, a := range group {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		var (
+			kvs []attribute.KeyValue
+			err error
+		)
+		if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+			kvs, err = e.marshalSlogGroup(a.Value.Group())
+		} else {
+			kvs, err = e.marshalSlogValue(structFiled{
+				attributeName:   a.Key,
+				attributePrefix: a.Key + ".",
+			}, a.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, kvs...)
+	}
+	return attrs, nil
+}
@@ -2,16 +2,31 @@ package otel
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// defaultExpandLimit is the maximum number of elements flattened by the `expand` option without an explicit limit.
+const defaultExpandLimit = 10
+
 type structFiled struct {
 	attributeName   string
 	filedName       string
 	filedIndex      int
 	omitEmpty       bool
+	omitZero        bool
 	attributePrefix string
+	expand          bool
+	expandLimit     int
+	columnar        bool
+	maxLen          int
+	maxItems        int
+	coerce          string
+	encoding        string
+	nilPolicy       string
 }
 
 var structFieldsCache = newCache[[]structFiled]()
@@ -36,27 +51,69 @@ func getStructFields(t reflect.Type) []structFiled {
 		if attributeName == "" {
 			attributeName = camelToSnake(f.Name)
 		}
-		attributePrefix := attributeName + "."
-		var omitEmpty bool
+		field := structFiled{
+			attributeName:   attributeName,
+			filedName:       f.Name,
+			filedIndex:      i,
+			attributePrefix: attributeName + ".",
+		}
 		for _, part := range tagParts[1:] {
-			if part == "omitempty" {
-				omitEmpty = true
+			name, value, _ := strings.Cut(part, "=")
+			switch name {
+			case "omitempty":
+				field.omitEmpty = true
+			case "omitzero":
+				field.omitZero = true
+			case "expand":
+				field.expand = true
+				field.expandLimit = parseLimit(value, defaultExpandLimit)
+			case "columnar":
+				field.columnar = true
+			case "maxlen":
+				field.maxLen = parseLimit(value, 0)
+			case "maxitems":
+				field.maxItems = parseLimit(value, 0)
+			case "type":
+				field.coerce = value
+			case "encoding":
+				field.encoding = value
+			case "nil":
+				field.nilPolicy = value
 			}
 		}
 
-		fields = append(fields, structFiled{
-			attributeName:   attributeName,
-			filedName:       f.Name,
-			filedIndex:      i,
-			omitEmpty:       omitEmpty,
-			attributePrefix: attributePrefix,
-		})
+		fields = append(fields, field)
 	}
 
 	structFieldsCache.set(t, fields)
 	return fields
 }
 
+// prefixed prepends the prefix of the field to the keys of attrs, which are marshaled from its nested value.
+func (s structFiled) prefixed(attrs []attribute.KeyValue) []attribute.KeyValue {
+	for i := range attrs {
+		attrs[i].Key = attribute.Key(s.attributePrefix) + attrs[i].Key
+	}
+	return attrs
+}
+
+// withTruncated appends a marker such as body.truncated=true to attrs if the value of the field was truncated.
+func (s structFiled) withTruncated(attrs []attribute.KeyValue, truncated bool) []attribute.KeyValue {
+	if !truncated {
+		return attrs
+	}
+	return append(attrs, attribute.Bool(s.attributePrefix+"truncated", true))
+}
+
+// parseLimit parses the value of a tag option such as `expand=5`, falling back to def if it is absent or invalid.
+func parseLimit(value string, def int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return def
+	}
+	return n
+}
+
 func camelToSnake(s string) string {
 	var result []rune
 	for i, r := range s {
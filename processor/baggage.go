@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Baggage is a custom SpanProcessor that copies the allowed members of the W3C baggage in the parent context to span attributes
+type Baggage struct {
+	nextProcessor trace.SpanProcessor
+	keyMapping    map[string]string
+	filter        func(key string) bool
+}
+
+// BaggageOption configures a Baggage processor.
+type BaggageOption func(*Baggage)
+
+// WithBaggageKeys allows only the given members to be copied, with the same keys as attributes.
+func WithBaggageKeys(keys ...string) BaggageOption {
+	return func(p *Baggage) {
+		for _, key := range keys {
+			p.keyMapping[key] = key
+		}
+	}
+}
+
+// WithBaggageKeyMapping allows only the members in mapping to be copied, renaming the keys of the baggage to the values.
+func WithBaggageKeyMapping(mapping map[string]string) BaggageOption {
+	return func(p *Baggage) {
+		for key, attrKey := range mapping {
+			p.keyMapping[key] = attrKey
+		}
+	}
+}
+
+// WithBaggageFilter allows the members whose keys satisfy filter to be copied, with the same keys as attributes.
+func WithBaggageFilter(filter func(key string) bool) BaggageOption {
+	return func(p *Baggage) {
+		p.filter = filter
+	}
+}
+
+// NewBaggage creates a new Baggage. The members to be copied must be allowed by the options,
+// since baggage is set by upstream callers and may carry arbitrary or sensitive data. Without options, nothing is copied.
+func NewBaggage(next trace.SpanProcessor, opts ...BaggageOption) *Baggage {
+	p := &Baggage{
+		nextProcessor: next,
+		keyMapping:    make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// OnStart is called when a span starts
+func (p *Baggage) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	setMissingAttributes(s, p.baggageAttributes(parent))
+	p.nextProcessor.OnStart(parent, s)
+}
+
+// OnEnd is called when a span ends
+func (p *Baggage) OnEnd(s trace.ReadOnlySpan) {
+	p.nextProcessor.OnEnd(s)
+}
+
+// Shutdown shuts down the processor.
+func (p *Baggage) Shutdown(ctx context.Context) error {
+	return p.nextProcessor.Shutdown(ctx)
+}
+
+// ForceFlush forces the processor to flush any buffered spans.
+func (p *Baggage) ForceFlush(ctx context.Context) error {
+	return p.nextProcessor.ForceFlush(ctx)
+}
+
+// baggageAttributes converts the allowed members of the baggage into string attributes, since baggage has no types.
+func (p *Baggage) baggageAttributes(ctx context.Context) []attribute.KeyValue {
+	if len(p.keyMapping) == 0 && p.filter == nil {
+		return nil
+	}
+
+	members := baggage.FromContext(ctx).Members()
+	attrs := make([]attribute.KeyValue, 0, len(members))
+	for _, member := range members {
+		key := member.Key()
+		if attrKey, ok := p.keyMapping[key]; ok {
+			attrs = append(attrs, attribute.String(attrKey, member.Value()))
+		} else if p.filter != nil && p.filter(key) {
+			attrs = append(attrs, attribute.String(key, member.Value()))
+		}
+	}
+	return attrs
+}
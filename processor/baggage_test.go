@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ebi-yade/spans"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestBaggage(t *testing.T) {
+	ctx, err := spans.ContextWithBaggage(context.Background(),
+		spans.ObjectAttr("tenant", tenant{ID: "t1", Plan: "free"}),
+		spans.StringAttr("experiment", "arm-b"),
+	)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		opts []BaggageOption
+		want []attribute.KeyValue
+	}{
+		{
+			name: "no options",
+			want: nil,
+		},
+		{
+			name: "filter",
+			opts: []BaggageOption{
+				WithBaggageFilter(func(key string) bool { return strings.HasPrefix(key, "tenant.") }),
+			},
+			want: []attribute.KeyValue{
+				attribute.String("tenant.id", "t1"),
+				attribute.String("tenant.plan", "free"),
+			},
+		},
+		{
+			name: "allowlist and mapping",
+			opts: []BaggageOption{
+				WithBaggageKeys("tenant.id", "missing"),
+				WithBaggageKeyMapping(map[string]string{"experiment": "experiment.arm"}),
+			},
+			want: []attribute.KeyValue{
+				attribute.String("tenant.id", "t1"),
+				attribute.String("experiment.arm", "arm-b"),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tracer, recorder := newTestTracer(t, func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+				return NewBaggage(next, c.opts...)
+			})
+			_, span := tracer.Start(ctx, "test")
+			span.End()
+
+			assertAttributes(t, c.want, recorder.Ended()[0].Attributes())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package processor provides SpanProcessors to set attributes carried by the parent context on spans.
+package processor
+
+import (
+	"context"
+
+	"github.com/ebi-yade/spans"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ContextAttrs is a custom SpanProcessor that sets the attributes carried by spans.ContextWithAttrs on spans
+type ContextAttrs struct {
+	nextProcessor trace.SpanProcessor
+}
+
+// NewContextAttrs creates a new ContextAttrs
+func NewContextAttrs(next trace.SpanProcessor) *ContextAttrs {
+	return &ContextAttrs{
+		nextProcessor: next,
+	}
+}
+
+// OnStart is called when a span starts
+func (p *ContextAttrs) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	setMissingAttributes(s, spans.AttrsFromContext(parent))
+	p.nextProcessor.OnStart(parent, s)
+}
+
+// OnEnd is called when a span ends
+func (p *ContextAttrs) OnEnd(s trace.ReadOnlySpan) {
+	p.nextProcessor.OnEnd(s)
+}
+
+// Shutdown shuts down the processor.
+func (p *ContextAttrs) Shutdown(ctx context.Context) error {
+	return p.nextProcessor.Shutdown(ctx)
+}
+
+// ForceFlush forces the processor to flush any buffered spans.
+func (p *ContextAttrs) ForceFlush(ctx context.Context) error {
+	return p.nextProcessor.ForceFlush(ctx)
+}
+
+// setMissingAttributes sets attrs on the span except for the keys that are already set at the start of the span,
+// so that the attributes given to the span explicitly take precedence.
+func setMissingAttributes(s trace.ReadWriteSpan, attrs []attribute.KeyValue) {
+	if len(attrs) == 0 {
+		return
+	}
+	existing := s.Attributes()
+	keys := make(map[attribute.Key]struct{}, len(existing))
+	for _, attr := range existing {
+		keys[attr.Key] = struct{}{}
+	}
+
+	missing := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if _, ok := keys[attr.Key]; !ok {
+			missing = append(missing, attr)
+		}
+	}
+	s.SetAttributes(missing...)
+}
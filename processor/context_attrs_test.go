@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ebi-yade/spans"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTracer(tb testing.TB, wrap func(sdktrace.SpanProcessor) sdktrace.SpanProcessor) (trace.Tracer, *tracetest.SpanRecorder) {
+	tb.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(wrap(recorder)))
+	tb.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	return tp.Tracer("test"), recorder
+}
+
+type tenant struct {
+	ID   string `otel:"id"`
+	Plan string `otel:"plan"`
+}
+
+func TestContextAttrs(t *testing.T) {
+	tracer, recorder := newTestTracer(t, func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+		return NewContextAttrs(next)
+	})
+
+	ctx := spans.ContextWithAttrs(context.Background(), spans.ObjectAttr("tenant", tenant{ID: "t1", Plan: "free"}))
+	ctx, parent := tracer.Start(ctx, "parent")
+	_, child := tracer.Start(ctx, "child", spans.WithAttrs(spans.StringAttr("tenant.plan", "explicit")))
+	child.End()
+	parent.End()
+
+	ended := recorder.Ended()
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("tenant.id", "t1"),
+		attribute.String("tenant.plan", "explicit"),
+	}, ended[0].Attributes())
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("tenant.id", "t1"),
+		attribute.String("tenant.plan", "free"),
+	}, ended[1].Attributes())
+}
+
+func assertAttributes(tb testing.TB, want, got []attribute.KeyValue, msgAndArgs ...interface{}) bool {
+	tb.Helper()
+
+	sortKV := func(i, j attribute.KeyValue) int {
+		return cmp.Compare(i.Key, j.Key)
+	}
+	slices.SortFunc(want, sortKV)
+	slices.SortFunc(got, sortKV)
+
+	return assert.Equal(tb, want, got, msgAndArgs...)
+}
@@ -0,0 +1,134 @@
+package spans
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlogHandlerOptions are options for the handler created by NewSlogHandler. A nil options is equivalent to the zero value.
+type SlogHandlerOptions struct {
+	// PromoteKeys are the keys of slog attributes to be set on the span as well as on the event.
+	// Keys in groups are joined with dots, e.g. "user.id" for slog.Group("user", slog.Int("id", 1)).
+	PromoteKeys []string
+
+	// ErrorStatus marks the span as codes.Error with the message of records at slog.LevelError or above.
+	ErrorStatus bool
+}
+
+// slogHandler is a slog.Handler that adds records to the span in the context as events, then passes them to the next handler.
+type slogHandler struct {
+	next    slog.Handler
+	opts    SlogHandlerOptions
+	promote map[attribute.Key]struct{}
+
+	attrs  []KeyValue
+	groups []string
+}
+
+// NewSlogHandler creates a slog.Handler that adds a span event named after the message for each record
+// whose context carries a recording span, then passes the record to next.
+// The attributes of the record are converted with the same rules as ObjectAttr, with groups as prefixes of the keys,
+// and the level is added as the "level" attribute. Records disabled by next are not added to spans either.
+func NewSlogHandler(next slog.Handler, opts *SlogHandlerOptions) slog.Handler {
+	h := &slogHandler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.promote = make(map[attribute.Key]struct{}, len(h.opts.PromoteKeys))
+	for _, key := range h.opts.PromoteKeys {
+		h.promote[attribute.Key(key)] = struct{}{}
+	}
+	return h
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the record to the span in ctx, then passes it to the next handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		h.addToSpan(span, r)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a new handler with attrs added to all the records, under the current groups.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append(slices.Clip(h.attrs), h.grouped(slogKeyValues(attrs))...)
+	h2.next = h.next.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes the keys of the following attributes with name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
+
+func (h *slogHandler) addToSpan(span trace.Span, r slog.Record) {
+	kvs := make([]KeyValue, 0, len(h.attrs)+r.NumAttrs())
+	kvs = append(kvs, h.attrs...)
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+	kvs = append(kvs, h.grouped(slogKeyValues(recordAttrs))...)
+
+	attributes := getStandardAttributes(kvs)
+	var promoted []attribute.KeyValue
+	for _, attr := range attributes {
+		if _, ok := h.promote[attr.Key]; ok {
+			promoted = append(promoted, attr)
+		}
+	}
+	if len(promoted) > 0 {
+		span.SetAttributes(promoted...)
+	}
+
+	opts := []trace.EventOption{
+		trace.WithAttributes(append(attributes, attribute.String(slog.LevelKey, r.Level.String()))...),
+	}
+	if !r.Time.IsZero() {
+		opts = append(opts, trace.WithTimestamp(r.Time))
+	}
+	span.AddEvent(r.Message, opts...)
+
+	if h.opts.ErrorStatus && r.Level >= slog.LevelError {
+		span.SetStatus(codes.Error, r.Message)
+	}
+}
+
+// grouped nests kvs under the current groups of the handler.
+func (h *slogHandler) grouped(kvs []KeyValue) []KeyValue {
+	if len(h.groups) == 0 || len(kvs) == 0 {
+		return kvs
+	}
+	return []KeyValue{Group(strings.Join(h.groups, "."), kvs...)}
+}
+
+// slogKeyValues converts slog attributes with SlogAttr.
+func slogKeyValues(attrs []slog.Attr) []KeyValue {
+	kvs := make([]KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kvs = append(kvs, SlogAttr(a))
+	}
+	return kvs
+}
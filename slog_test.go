@@ -0,0 +1,94 @@
+package spans
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type testLogValuer struct {
+	ID   int
+	Name string
+}
+
+func (v testLogValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", v.ID))
+}
+
+func TestSlogHandler(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil), &SlogHandlerOptions{
+		PromoteKeys: []string{"request.user.id"},
+		ErrorStatus: true,
+	}))
+
+	ctx, span := tracer.Start(context.Background(), "test")
+	logger = logger.With(slog.String("service", "api")).WithGroup("request")
+	logger.InfoContext(ctx, "handled",
+		slog.Any("user", testLogValuer{ID: 1, Name: "foo"}),
+		slog.Group("db", slog.Int("rows", 2)),
+		slog.Any("object", testObject{ID: 3}),
+	)
+	logger.ErrorContext(ctx, "failed", "err", errors.New("boom"))
+	logger.DebugContext(ctx, "disabled")
+	span.End()
+
+	assert.Contains(t, buf.String(), `"request":{"user":{"id":1}`)
+	ended := recorder.Ended()[0]
+	events := ended.Events()
+	if !assert.Len(t, events, 2) {
+		return
+	}
+	assert.Equal(t, "handled", events[0].Name)
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("service", "api"),
+		attribute.Int("request.user.id", 1),
+		attribute.Int("request.db.rows", 2),
+		attribute.Int("request.object.id", 3),
+		attribute.String("request.object.name", ""),
+		attribute.Bool("request.object.admin", false),
+		attribute.String("level", "INFO"),
+	}, events[0].Attributes)
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.String("service", "api"),
+		attribute.String("request.err", "boom"),
+		attribute.String("level", "ERROR"),
+	}, events[1].Attributes)
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.Int("request.user.id", 1),
+	}, ended.Attributes())
+	assert.Equal(t, codes.Error, ended.Status().Code)
+	assert.Equal(t, "failed", ended.Status().Description)
+}
+
+func TestSlogHandler__NotRecording(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewTextHandler(&buf, nil), nil))
+
+	logger.InfoContext(context.Background(), "no span", slog.Int("n", 1))
+	assert.Contains(t, buf.String(), "msg=\"no span\" n=1")
+}
+
+func TestSlogAttr(t *testing.T) {
+	got := ToAttributes(
+		SlogAttr(slog.Any("user", testLogValuer{ID: 1, Name: "foo"})),
+		SlogAttr(slog.Group("", slog.String("inline", "yes"))),
+		SlogAttr(slog.Any("", testLogValuer{ID: 2})),
+		SlogAttr(slog.Group("http", slog.Int("status", 200), slog.Group("route", slog.String("name", "index")))),
+		SlogAttr(slog.Attr{}),
+	)
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.Int("user.id", 1),
+		attribute.String("inline", "yes"),
+		attribute.Int("id", 2),
+		attribute.Int("http.status", 200),
+		attribute.String("http.route.name", "index"),
+	}, got)
+}
@@ -3,6 +3,8 @@ package spans
 import (
 	"fmt"
 	"log/slog"
+	"reflect"
+	"time"
 
 	pkgotel "github.com/ebi-yade/spans/pkg/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -18,33 +20,81 @@ func newKeyValue(k string, v any) KeyValue {
 	return KeyValue{key: attribute.Key(k), value: v}
 }
 
+// Key returns the key of the KeyValue, which becomes the prefix of the attributes for structs and maps.
+func (kv KeyValue) Key() attribute.Key {
+	return kv.key
+}
+
+// Value returns the value of the KeyValue: an attribute.Value for the compatible constructors such as IntAttr,
+// the nested []KeyValue for Group, the result of the function for LazyAttr, or the original value for ObjectAttr.
+func (kv KeyValue) Value() any {
+	switch v := kv.value.(type) {
+	case lazyValue:
+		return v()
+	case groupValue:
+		return []KeyValue(v)
+	default:
+		return v
+	}
+}
+
+// lazyValue is the value of KeyValue created by LazyAttr, which is evaluated only on conversion.
+type lazyValue func() any
+
+// groupValue is the value of KeyValue created by Group.
+type groupValue []KeyValue
+
 func getStandardAttributes(attrs []KeyValue) []attribute.KeyValue {
+	return getEncodedAttributes(pkgotel.DefaultEncoder(), attrs)
+}
+
+func getEncodedAttributes(encoder *pkgotel.Encoder, attrs []KeyValue) []attribute.KeyValue {
 	attributes := make([]attribute.KeyValue, 0, len(attrs))
 	for _, attr := range attrs {
-		switch v := attr.value.(type) {
-		case attribute.Value:
-			attributes = append(attributes, attribute.KeyValue{Key: attr.key, Value: v})
-		default:
-			results, err := pkgotel.MarshalOtelAttributes(v)
-			if err != nil { // 解釈に失敗した場合はログを吐いて無視するようにしている
-				slog.Error(fmt.Sprintf("error MarshalOtelAttributes: key=>%s, type=>%T, error=>%v", attr.key, v, err))
-			}
-			for _, res := range results {
-				attributes = append(attributes, attribute.KeyValue{
-					Key:   attribute.Key(fmt.Sprintf("%s.%s", attr.key, res.Key)),
-					Value: res.Value,
-				})
-			}
-		}
+		attributes = appendStandardAttributes(encoder, attributes, attr.key, attr.value)
 	}
 
 	return attributes
 }
 
+func appendStandardAttributes(encoder *pkgotel.Encoder, attributes []attribute.KeyValue, key attribute.Key, value any) []attribute.KeyValue {
+	switch v := value.(type) {
+	case attribute.Value:
+		return append(attributes, attribute.KeyValue{Key: key, Value: v})
+	case lazyValue:
+		return appendStandardAttributes(encoder, attributes, key, v())
+	case groupValue:
+		for _, attr := range v {
+			childKey := attr.key
+			if key != "" {
+				childKey = key + "." + attr.key
+			}
+			attributes = appendStandardAttributes(encoder, attributes, childKey, attr.value)
+		}
+		return attributes
+	default:
+		results, err := encoder.MarshalWithKey(string(key), v)
+		if err != nil { // 解釈に失敗した場合はログを吐いて無視するようにしている
+			slog.Error(fmt.Sprintf("error MarshalWithKey: key=>%s, type=>%T, error=>%v", key, v, err))
+		}
+		return append(attributes, results...)
+	}
+}
+
 // ============================================================================
 // Extended APIs to support flexible data types while keeping code simple
 // ============================================================================
 
+// ToAttributes converts KeyValues into attributes, which can be used for metric instruments or span events.
+func ToAttributes(kvs ...KeyValue) []attribute.KeyValue {
+	return getStandardAttributes(kvs)
+}
+
+// AttributeSet converts KeyValues into an attribute.Set, e.g. for metric.WithAttributeSet.
+func AttributeSet(kvs ...KeyValue) attribute.Set {
+	return attribute.NewSet(getStandardAttributes(kvs)...)
+}
+
 // WithAttrs can be used in place of trace.WithAttributes to set multiple attributes on a span at the time of creation.
 func WithAttrs(attrs ...KeyValue) trace.SpanStartEventOption {
 	attributes := getStandardAttributes(attrs)
@@ -52,12 +102,32 @@ func WithAttrs(attrs ...KeyValue) trace.SpanStartEventOption {
 }
 
 // SetAttrs can be used in place of span.SetAttributes to set multiple attributes on a span after it has been created.
+// It does nothing if the span is not recording, e.g. not sampled, to avoid the cost of marshaling.
 func SetAttrs(span trace.Span, attrs ...KeyValue) {
+	if !span.IsRecording() {
+		return
+	}
 	attributes := getStandardAttributes(attrs)
 	span.SetAttributes(attributes...)
 }
 
+// AddEvent can be used in place of span.AddEvent to add an event with multiple attributes on a span.
+// It does nothing if the span is not recording, like SetAttrs.
+func AddEvent(span trace.Span, name string, attrs ...KeyValue) {
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(name, trace.WithAttributes(getStandardAttributes(attrs)...))
+}
+
+// WithEventAttrs can be used in place of trace.WithAttributes to set multiple attributes on an event, e.g. with span.RecordError.
+func WithEventAttrs(attrs ...KeyValue) trace.EventOption {
+	attributes := getStandardAttributes(attrs)
+	return trace.WithAttributes(attributes...)
+}
+
 // ObjectAttr は構造体や map などの複合型を属性として設定するための KeyValue を生成します。
+// スカラー値やスライスを渡した場合は k をそのままキーとする単一の属性になるため、ジェネリクスの型パラメータの値なども扱えます。
 // 綺麗な型制約や命名を提供できなかったのはご愛嬌として、以下の点にご注意ください。
 //   - プリミティブ型以外の配列、またはそれを子として持つ構造体や map は構造上受け入れられない（JSONとして出力される）
 //   - map のキーの基底型が string でない場合は <T value> が属性のキーとして採用される
@@ -65,6 +135,77 @@ func ObjectAttr(k string, v interface{}) KeyValue {
 	return newKeyValue(k, v)
 }
 
+// LazyAttr creates a KeyValue whose value is computed by fn only if it is needed,
+// e.g. SetAttrs never calls fn for spans that are not recording.
+// Note that WithAttrs always calls fn since the sampling decision has not been made at the time.
+func LazyAttr(k string, fn func() any) KeyValue {
+	return newKeyValue(k, lazyValue(fn))
+}
+
+// Group creates a KeyValue that prefixes the keys of attrs with k, like slog.Group.
+// For example, Group("db", StringAttr("system", "pg")) results in db.system, and groups can be nested.
+// If k is empty, attrs are inlined without any prefix.
+func Group(k string, attrs ...KeyValue) KeyValue {
+	return newKeyValue(k, groupValue(attrs))
+}
+
+// Attr is a type-safe constructor of KeyValue for any type.
+// Primitive types, their slices, time.Time and fmt.Stringer are converted into attribute.Value at construction
+// without reflection, and the other types are deferred to the struct marshaler like ObjectAttr.
+// fmt.Stringer is also deferred if the marshaler has its own representation, e.g. slog.LogValuer or RegisterEncoder.
+func Attr[T any](k string, v T) KeyValue {
+	switch v := any(v).(type) {
+	case bool:
+		return BoolAttr(k, v)
+	case []bool:
+		return BoolSliceAttr(k, v)
+	case int:
+		return IntAttr(k, v)
+	case []int:
+		return IntSliceAttr(k, v)
+	case int8:
+		return Int64Attr(k, int64(v))
+	case int16:
+		return Int64Attr(k, int64(v))
+	case int32:
+		return Int64Attr(k, int64(v))
+	case int64:
+		return Int64Attr(k, v)
+	case []int64:
+		return Int64SliceAttr(k, v)
+	case uint:
+		return Int64Attr(k, int64(v))
+	case uint8:
+		return Int64Attr(k, int64(v))
+	case uint16:
+		return Int64Attr(k, int64(v))
+	case uint32:
+		return Int64Attr(k, int64(v))
+	case uint64:
+		return Int64Attr(k, int64(v))
+	case float32:
+		return Float64Attr(k, float64(v))
+	case float64:
+		return Float64Attr(k, v)
+	case []float64:
+		return Float64SliceAttr(k, v)
+	case string:
+		return StringAttr(k, v)
+	case []string:
+		return StringSliceAttr(k, v)
+	case time.Time:
+		return StringAttr(k, v.Format(time.RFC3339Nano))
+	case fmt.Stringer:
+		// nil pointers and types with their own representation as attributes are left to the marshaler
+		if rv := reflect.ValueOf(v); (rv.Kind() == reflect.Ptr && rv.IsNil()) || pkgotel.DefaultEncoder().CanMarshal(v) {
+			return ObjectAttr(k, v)
+		}
+		return StringerAttr(k, v)
+	default:
+		return ObjectAttr(k, v)
+	}
+}
+
 // ============================================================================
 // Compatible APIs to initialize KeyValue
 // ============================================================================
@@ -123,3 +264,16 @@ func StringSliceAttr(k string, v []string) KeyValue {
 func StringerAttr(k string, v fmt.Stringer) KeyValue {
 	return newKeyValue(k, attribute.StringValue(v.String()))
 }
+
+// SlogAttr converts a slog.Attr into a KeyValue, so that types implementing slog.LogValuer drive both logs and traces.
+// The value is resolved on conversion into attributes, and groups become prefixes of the keys like Group.
+func SlogAttr(a slog.Attr) KeyValue {
+	if a.Key == "" {
+		// slog inlines a group with an empty key after resolving it, so it must be resolved here to be inlined
+		a.Value = a.Value.Resolve()
+	}
+	if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+		return Group("", slogKeyValues(a.Value.Group())...)
+	}
+	return newKeyValue(a.Key, a.Value)
+}
@@ -0,0 +1,298 @@
+package spans
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testStatus int
+
+func (s testStatus) String() string {
+	return fmt.Sprintf("status_%d", s)
+}
+
+type testObject struct {
+	ID    int    `otel:"id"`
+	Name  string `otel:"name"`
+	Admin bool   `otel:"admin"`
+}
+
+func TestAttr(t *testing.T) {
+	args := []KeyValue{
+		Attr("bool", true),
+		Attr("int", 1),
+		Attr("uint8", uint8(2)),
+		Attr("float", 3.14),
+		Attr("string", "hello"),
+		Attr("strings", []string{"a", "b"}),
+		Attr("time", time.Time{}),
+		Attr("stringer", testStatus(1)),
+		Attr("object", testObject{ID: 1, Name: "foo"}),
+		Attr("bytes", []byte("hi")),
+	}
+	want := []attribute.KeyValue{
+		attribute.Bool("bool", true),
+		attribute.Int("int", 1),
+		attribute.Int64("uint8", 2),
+		attribute.Float64("float", 3.14),
+		attribute.String("string", "hello"),
+		attribute.StringSlice("strings", []string{"a", "b"}),
+		attribute.String("time", "0001-01-01T00:00:00Z"),
+		attribute.String("stringer", "status_1"),
+		attribute.Int64("object.id", 1),
+		attribute.String("object.name", "foo"),
+		attribute.Bool("object.admin", false),
+		attribute.String("bytes", "aGk="),
+	}
+	got := ToAttributes(args...)
+	assertAttributes(t, want, got)
+}
+
+type testToken string
+
+func (t testToken) String() string {
+	return string(t)
+}
+
+func (t testToken) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+func TestAttr__Stringer(t *testing.T) {
+	args := []KeyValue{
+		Attr("nil", (*testStatus)(nil)),
+		Attr("token", testToken("secret")),
+	}
+	want := []attribute.KeyValue{
+		attribute.String("token", "REDACTED"),
+	}
+	got := ToAttributes(args...)
+	assertAttributes(t, want, got)
+	assertAttributes(t, ToAttributes(ObjectAttr("token", testToken("secret"))), got)
+}
+
+func TestGroup(t *testing.T) {
+	args := []KeyValue{
+		Group("db",
+			StringAttr("system", "pg"),
+			ObjectAttr("query", testObject{ID: 1, Name: "foo"}),
+			Group("pool", IntAttr("size", 10)),
+		),
+		Group("", StringAttr("inlined", "yes")),
+	}
+	want := []attribute.KeyValue{
+		attribute.String("db.system", "pg"),
+		attribute.Int64("db.query.id", 1),
+		attribute.String("db.query.name", "foo"),
+		attribute.Bool("db.query.admin", false),
+		attribute.Int("db.pool.size", 10),
+		attribute.String("inlined", "yes"),
+	}
+	got := ToAttributes(args...)
+	assertAttributes(t, want, got)
+}
+
+func TestKeyValue_Accessors(t *testing.T) {
+	obj := testObject{ID: 1}
+	str := StringAttr("str", "hello")
+	assert.Equal(t, attribute.Key("str"), str.Key())
+	assert.Equal(t, attribute.StringValue("hello"), str.Value())
+
+	object := ObjectAttr("object", obj)
+	assert.Equal(t, attribute.Key("object"), object.Key())
+	assert.Equal(t, obj, object.Value())
+
+	lazy := LazyAttr("lazy", func() any { return 1 })
+	assert.Equal(t, 1, lazy.Value())
+
+	group := Group("group", str)
+	assert.Equal(t, []KeyValue{str}, group.Value())
+}
+
+func TestAttributeSet(t *testing.T) {
+	set := AttributeSet(
+		StringAttr("str", "hello"),
+		ObjectAttr("object", testObject{ID: 1, Name: "foo"}),
+	)
+	want := attribute.NewSet(
+		attribute.String("str", "hello"),
+		attribute.Int64("object.id", 1),
+		attribute.String("object.name", "foo"),
+		attribute.Bool("object.admin", false),
+	)
+	assert.True(t, want.Equals(&set), "want %v, got %v", want.Encoded(attribute.DefaultEncoder()), set.Encoded(attribute.DefaultEncoder()))
+}
+
+func TestAddEvent(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	_, span := tracer.Start(context.Background(), "test")
+	AddEvent(span, "order.validated", ObjectAttr("order", testObject{ID: 1, Name: "foo"}))
+	span.AddEvent("order.shipped", WithEventAttrs(StringAttr("carrier", "yamato")))
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "order.validated", events[0].Name)
+		assertAttributes(t, []attribute.KeyValue{
+			attribute.Int64("order.id", 1),
+			attribute.String("order.name", "foo"),
+			attribute.Bool("order.admin", false),
+		}, events[0].Attributes)
+		assert.Equal(t, "order.shipped", events[1].Name)
+		assertAttributes(t, []attribute.KeyValue{
+			attribute.String("carrier", "yamato"),
+		}, events[1].Attributes)
+	}
+}
+
+func BenchmarkIntAttr(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{IntAttr("key", i)})
+	}
+}
+
+func BenchmarkAttr__Int(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{Attr("key", i)})
+	}
+}
+
+func BenchmarkStringSliceAttr(b *testing.B) {
+	v := []string{"a", "b", "c"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{StringSliceAttr("key", v)})
+	}
+}
+
+func BenchmarkAttr__StringSlice(b *testing.B) {
+	v := []string{"a", "b", "c"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{Attr("key", v)})
+	}
+}
+
+func BenchmarkObjectAttr__Int(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{ObjectAttr("key", i)})
+	}
+}
+
+func BenchmarkObjectAttr__Struct(b *testing.B) {
+	v := testObject{ID: 1, Name: "foo"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{ObjectAttr("key", v)})
+	}
+}
+
+func BenchmarkAttr__Struct(b *testing.B) {
+	v := testObject{ID: 1, Name: "foo"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = getStandardAttributes([]KeyValue{Attr("key", v)})
+	}
+}
+
+func newTestTracer(tb testing.TB) (trace.Tracer, *tracetest.SpanRecorder) {
+	tb.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tb.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	return tp.Tracer("test"), recorder
+}
+
+func TestSetAttrs__LazyAttr(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	var called int
+	lazy := LazyAttr("lazy", func() any {
+		called++
+		return testObject{ID: 1}
+	})
+
+	nonRecording := trace.SpanFromContext(context.Background())
+	SetAttrs(nonRecording, lazy)
+	assert.Equal(t, 0, called)
+
+	_, span := tracer.Start(context.Background(), "recording")
+	SetAttrs(span, lazy)
+	span.End()
+	assert.Equal(t, 1, called)
+
+	want := []attribute.KeyValue{
+		attribute.Int64("lazy.id", 1),
+		attribute.String("lazy.name", ""),
+		attribute.Bool("lazy.admin", false),
+	}
+	assertAttributes(t, want, recorder.Ended()[0].Attributes())
+}
+
+func TestSetAttrs__NonRecordingAllocations(t *testing.T) {
+	span := trace.SpanFromContext(context.Background())
+	attrs := []KeyValue{
+		ObjectAttr("object", testObject{ID: 1, Name: "foo"}),
+		LazyAttr("lazy", func() any { return testObject{ID: 2} }),
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		SetAttrs(span, attrs...)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkSetAttrs__NonRecording(b *testing.B) {
+	span := trace.SpanFromContext(context.Background())
+	v := testObject{ID: 1, Name: "foo"}
+	attrs := []KeyValue{
+		ObjectAttr("object", v),
+		LazyAttr("lazy", func() any { return v }),
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SetAttrs(span, attrs...)
+	}
+}
+
+func BenchmarkSetAttrs__Recording(b *testing.B) {
+	tracer, _ := newTestTracer(b)
+	_, span := tracer.Start(context.Background(), "recording")
+	defer span.End()
+	v := testObject{ID: 1, Name: "foo"}
+	attrs := []KeyValue{
+		ObjectAttr("object", v),
+		LazyAttr("lazy", func() any { return v }),
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SetAttrs(span, attrs...)
+	}
+}
+
+func assertAttributes(tb testing.TB, want, got []attribute.KeyValue, msgAndArgs ...interface{}) bool {
+	tb.Helper()
+
+	sortKV := func(i, j attribute.KeyValue) int {
+		return cmp.Compare(i.Key, j.Key)
+	}
+	slices.SortFunc(want, sortKV)
+	slices.SortFunc(got, sortKV)
+
+	return assert.Equal(tb, want, got, msgAndArgs...)
+}
@@ -0,0 +1,68 @@
+package spans
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Start starts a span with multiple attributes, and returns the function to end it.
+// The function is intended to be deferred with the pointer to the named error result of the caller:
+//
+//	func handle(ctx context.Context) (err error) {
+//		ctx, end := spans.Start(ctx, tracer, "handle", spans.ObjectAttr("request", req))
+//		defer end(&err)
+//		...
+//	}
+//
+// If the error is not nil, it is recorded with RecordError, which sets the status to codes.Error.
+// If the caller panics, the panic is recorded as an exception event and the function panics again.
+// For a local root span, the returned context also has a collector for Collect and Accumulate,
+// whose attributes are flushed onto the span at the end.
+func Start(ctx context.Context, tracer trace.Tracer, name string, attrs ...KeyValue) (context.Context, func(*error)) {
+	parent := trace.SpanContextFromContext(ctx)
+	ctx, span := tracer.Start(ctx, name, WithAttrs(attrs...))
+	collects := (!parent.IsValid() || parent.IsRemote()) && span.IsRecording()
+	if collects {
+		ctx = ContextWithCollector(ctx)
+	}
+	return ctx, func(errp *error) {
+		if collects {
+			FlushCollected(ctx, span)
+		}
+		// recover works here because this function itself is deferred by the caller
+		if r := recover(); r != nil {
+			recordPanic(span, r)
+			span.End()
+			panic(r)
+		}
+		if errp != nil {
+			RecordError(span, *errp)
+		}
+		span.End()
+	}
+}
+
+// recordPanic records a recovered value as an exception event that escapes the span, with the stack trace of the panic.
+func recordPanic(span trace.Span, r any) {
+	if !span.IsRecording() {
+		return
+	}
+	message := fmt.Sprint(r)
+	attributes := []attribute.KeyValue{
+		semconv.ExceptionType(fmt.Sprintf("%T", r)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(string(debug.Stack())),
+		semconv.ExceptionEscaped(true),
+	}
+	if err, ok := r.(error); ok {
+		attributes = append(attributes, getErrorAttributes(err)...)
+	}
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(attributes...))
+	span.SetStatus(codes.Error, "panic: "+message)
+}
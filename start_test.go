@@ -0,0 +1,72 @@
+package spans
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStart(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+
+	succeed := func(ctx context.Context) (err error) {
+		_, end := Start(ctx, tracer, "succeed", StringAttr("key", "value"))
+		defer end(&err)
+		return nil
+	}
+	fail := func(ctx context.Context) (err error) {
+		_, end := Start(ctx, tracer, "fail")
+		defer end(&err)
+		return &testDomainError{Code: "invalid", OrderID: 1}
+	}
+	panics := func(ctx context.Context) (err error) {
+		_, end := Start(ctx, tracer, "panic")
+		defer end(&err)
+		panic(errors.New("boom"))
+	}
+
+	assert.NoError(t, succeed(context.Background()))
+	assert.Error(t, fail(context.Background()))
+	assert.PanicsWithError(t, "boom", func() {
+		_ = panics(context.Background())
+	})
+
+	ended := recorder.Ended()
+	if !assert.Len(t, ended, 3) {
+		return
+	}
+
+	assert.Equal(t, "succeed", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assertAttributes(t, []attribute.KeyValue{attribute.String("key", "value")}, ended[0].Attributes())
+
+	assert.Equal(t, "fail", ended[1].Name())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Len(t, ended[1].Events(), 1)
+
+	assert.Equal(t, "panic", ended[2].Name())
+	assert.Equal(t, codes.Error, ended[2].Status().Code)
+	assert.Equal(t, "panic: boom", ended[2].Status().Description)
+	if assert.Len(t, ended[2].Events(), 1) {
+		eventAttrs := attribute.NewSet(ended[2].Events()[0].Attributes...)
+		escaped, _ := eventAttrs.Value("exception.escaped")
+		assert.True(t, escaped.AsBool())
+		_, ok := eventAttrs.Value("exception.stacktrace")
+		assert.True(t, ok)
+	}
+}
+
+func TestStart__NilErrorPointer(t *testing.T) {
+	tracer, recorder := newTestTracer(t)
+	_, end := Start(context.Background(), tracer, "test")
+	end(nil)
+
+	if assert.Len(t, recorder.Ended(), 1) {
+		assert.Equal(t, trace.SpanKindInternal, recorder.Ended()[0].SpanKind())
+	}
+}
@@ -0,0 +1,98 @@
+package spans
+
+import (
+	"context"
+
+	pkgotel "github.com/ebi-yade/spans/pkg/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer wraps trace.Tracer to start spans with KeyValues directly, applying the default attributes to every span.
+type Tracer struct {
+	tracer       trace.Tracer
+	encoder      *pkgotel.Encoder
+	defaultAttrs []attribute.KeyValue
+}
+
+type tracerConfig struct {
+	encoder      *pkgotel.Encoder
+	defaultAttrs []KeyValue
+}
+
+// TracerOption configures a Tracer.
+type TracerOption func(*tracerConfig)
+
+// WithDefaultAttrs sets the attributes applied to every span started by the Tracer,
+// such as the name and the version of the component. They are converted only once when the Tracer is created.
+func WithDefaultAttrs(attrs ...KeyValue) TracerOption {
+	return func(c *tracerConfig) {
+		c.defaultAttrs = append(c.defaultAttrs, attrs...)
+	}
+}
+
+// WithEncoder sets the Encoder used by the Tracer instead of the default one, e.g. to apply limits only to a library.
+// Passing nil uses the default one.
+func WithEncoder(encoder *pkgotel.Encoder) TracerOption {
+	return func(c *tracerConfig) {
+		if encoder == nil {
+			encoder = pkgotel.DefaultEncoder()
+		}
+		c.encoder = encoder
+	}
+}
+
+// NewTracer creates a new Tracer
+func NewTracer(tracer trace.Tracer, opts ...TracerOption) *Tracer {
+	c := &tracerConfig{
+		encoder: pkgotel.DefaultEncoder(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &Tracer{
+		tracer:       tracer,
+		encoder:      c.encoder,
+		defaultAttrs: getEncodedAttributes(c.encoder, c.defaultAttrs),
+	}
+}
+
+// StartOption is an option of Tracer.Start, which is either a KeyValue or created by WithSpanOptions.
+type StartOption interface {
+	applyStart(*startConfig)
+}
+
+type startConfig struct {
+	attrs       []KeyValue
+	spanOptions []trace.SpanStartOption
+}
+
+func (kv KeyValue) applyStart(c *startConfig) {
+	c.attrs = append(c.attrs, kv)
+}
+
+type spanOptions []trace.SpanStartOption
+
+func (o spanOptions) applyStart(c *startConfig) {
+	c.spanOptions = append(c.spanOptions, o...)
+}
+
+// WithSpanOptions passes options of trace.Tracer.Start such as trace.WithSpanKind through Tracer.Start.
+func WithSpanOptions(opts ...trace.SpanStartOption) StartOption {
+	return spanOptions(opts)
+}
+
+// Start starts a span with the default attributes of the Tracer followed by the given attributes,
+// which take precedence if the keys are duplicated.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, trace.Span) {
+	c := &startConfig{}
+	for _, opt := range opts {
+		opt.applyStart(c)
+	}
+
+	attributes := make([]attribute.KeyValue, 0, len(t.defaultAttrs)+len(c.attrs))
+	attributes = append(attributes, t.defaultAttrs...)
+	attributes = append(attributes, getEncodedAttributes(t.encoder, c.attrs)...)
+	spanOpts := append([]trace.SpanStartOption{trace.WithAttributes(attributes...)}, c.spanOptions...)
+	return t.tracer.Start(ctx, name, spanOpts...)
+}
@@ -0,0 +1,63 @@
+package spans
+
+import (
+	"context"
+	"testing"
+
+	pkgotel "github.com/ebi-yade/spans/pkg/otel"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer_Start(t *testing.T) {
+	sdkTracer, recorder := newTestTracer(t)
+	tracer := NewTracer(sdkTracer,
+		WithDefaultAttrs(
+			ObjectAttr("component", struct {
+				Name    string
+				Version string
+			}{Name: "billing", Version: "v1.2.3"}),
+			StringAttr("overridden", "default"),
+		),
+		WithEncoder(pkgotel.NewEncoder(pkgotel.WithMaxLen(4))),
+	)
+
+	_, span := tracer.Start(context.Background(), "test",
+		StringAttr("overridden", "call"),
+		ObjectAttr("object", testObject{ID: 1, Name: "foo"}),
+		WithSpanOptions(trace.WithSpanKind(trace.SpanKindServer)),
+	)
+	span.End()
+
+	got := recorder.Ended()[0]
+	assert.Equal(t, trace.SpanKindServer, got.SpanKind())
+	want := []attribute.KeyValue{
+		attribute.String("component.name", "bill"),
+		attribute.Bool("component.name.truncated", true),
+		attribute.String("component.version", "v1.2"),
+		attribute.Bool("component.version.truncated", true),
+		attribute.String("overridden", "call"),
+		attribute.Int64("object.id", 1),
+		attribute.String("object.name", "foo"),
+		attribute.Bool("object.admin", false),
+	}
+	assertAttributes(t, want, got.Attributes())
+}
+
+func TestNewTracer__WithNilEncoder(t *testing.T) {
+	sdkTracer, recorder := newTestTracer(t)
+	tracer := NewTracer(sdkTracer,
+		WithDefaultAttrs(ObjectAttr("object", testObject{ID: 1})),
+		WithEncoder(nil),
+	)
+
+	_, span := tracer.Start(context.Background(), "test")
+	span.End()
+
+	assertAttributes(t, []attribute.KeyValue{
+		attribute.Int64("object.id", 1),
+		attribute.String("object.name", ""),
+		attribute.Bool("object.admin", false),
+	}, recorder.Ended()[0].Attributes())
+}